@@ -0,0 +1,54 @@
+package cache
+
+import "time"
+
+// Cache drivers.
+const (
+	DriverNone  = "none"
+	DriverLRU   = "lru"
+	DriverRedis = "redis"
+)
+
+type Config struct {
+	Driver    string        `yaml:"driver"`
+	Entries   int           `yaml:"entries"`
+	MaxBytes  int64         `yaml:"max_bytes"`
+	StoreTTL  time.Duration `yaml:"store_ttl"`
+	SearchTTL time.Duration `yaml:"search_ttl"`
+	UserTTL   time.Duration `yaml:"user_ttl"`
+	Redis     RedisConfig   `yaml:"redis"`
+}
+
+// Cache stores encoded values by key, a zero TTL keeps them until evicted.
+type Cache interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(keys ...string) error
+}
+
+// New opens the cache of the configured driver, no driver disables caching.
+func New(cfg *Config) Cache {
+	switch cfg.Driver {
+	case DriverLRU:
+		return NewLRU(cfg.Entries, cfg.MaxBytes)
+	case DriverRedis:
+		return NewRedis(&cfg.Redis)
+	default:
+		return Nop{}
+	}
+}
+
+// Nop never keeps anything, every lookup misses.
+type Nop struct{}
+
+func (Nop) Get(key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (Nop) Set(key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (Nop) Delete(keys ...string) error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU keeps the most recently used values in memory, evicting the least
+// recently used ones past the entry or byte limits. Zero limits are unbounded.
+type LRU struct {
+	mu       sync.Mutex
+	entries  int
+	maxBytes int64
+	bytes    int64
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func NewLRU(entries int, maxBytes int64) *LRU {
+	return &LRU{
+		entries:  entries,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+func (l *LRU) Get(key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	item, ok := l.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := item.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.remove(item)
+		return nil, false, nil
+	}
+
+	l.order.MoveToFront(item)
+	return entry.value, true, nil
+}
+
+func (l *LRU) Set(key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if item, ok := l.items[key]; ok {
+		l.remove(item)
+	}
+	if l.maxBytes > 0 && int64(len(value)) > l.maxBytes {
+		return nil
+	}
+
+	entry := &lruEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	l.items[key] = l.order.PushFront(entry)
+	l.bytes += int64(len(value))
+
+	for (l.entries > 0 && l.order.Len() > l.entries) || (l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.remove(l.order.Back())
+	}
+	return nil
+}
+
+func (l *LRU) Delete(keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if item, ok := l.items[key]; ok {
+			l.remove(item)
+		}
+	}
+	return nil
+}
+
+func (l *LRU) remove(item *list.Element) {
+	entry := l.order.Remove(item).(*lruEntry)
+	delete(l.items, entry.key)
+	l.bytes -= int64(len(entry.value))
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  int
+		maxBytes int64
+		ops      []string
+		want     []string
+		evicted  []string
+	}{
+		{
+			name:    "entry limit drops the oldest",
+			entries: 2,
+			ops:     []string{"set a", "set b", "set c"},
+			want:    []string{"b", "c"},
+			evicted: []string{"a"},
+		},
+		{
+			name:    "reads keep an entry recent",
+			entries: 2,
+			ops:     []string{"set a", "set b", "get a", "set c"},
+			want:    []string{"a", "c"},
+			evicted: []string{"b"},
+		},
+		{
+			name:     "byte limit drops until it fits",
+			maxBytes: 8,
+			ops:      []string{"set a", "set b", "set c"},
+			want:     []string{"b", "c"},
+			evicted:  []string{"a"},
+		},
+		{
+			name: "no limits keep everything",
+			ops:  []string{"set a", "set b", "set c"},
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLRU(tt.entries, tt.maxBytes)
+			for _, op := range tt.ops {
+				action, key, _ := strings.Cut(op, " ")
+				if action == "get" {
+					l.Get(key)
+				} else {
+					l.Set(key, []byte("four"), 0)
+				}
+			}
+
+			for _, key := range tt.want {
+				if _, ok, _ := l.Get(key); !ok {
+					t.Errorf("%q was evicted", key)
+				}
+			}
+			for _, key := range tt.evicted {
+				if _, ok, _ := l.Get(key); ok {
+					t.Errorf("%q wasn't evicted", key)
+				}
+			}
+		})
+	}
+}
+
+func TestLRUSkipsValuesOverTheByteLimit(t *testing.T) {
+	l := NewLRU(0, 4)
+	l.Set("small", []byte("four"), 0)
+	l.Set("large", []byte("five!"), 0)
+
+	if _, ok, _ := l.Get("large"); ok {
+		t.Error("value over the byte limit was kept")
+	}
+	if _, ok, _ := l.Get("small"); !ok {
+		t.Error("value over the byte limit evicted the others")
+	}
+}
+
+func TestLRUTTL(t *testing.T) {
+	l := NewLRU(0, 0)
+	l.Set("short", []byte("v"), 10*time.Millisecond)
+	l.Set("forever", []byte("v"), 0)
+
+	if _, ok, _ := l.Get("short"); !ok {
+		t.Fatal("value expired before its TTL")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := l.Get("short"); ok {
+		t.Error("value outlived its TTL")
+	}
+	if _, ok, _ := l.Get("forever"); !ok {
+		t.Error("value without TTL expired")
+	}
+	if l.bytes != 1 {
+		t.Errorf("bytes = %d after expiring, want 1", l.bytes)
+	}
+}
+
+func TestLRUSetReplacesAndDelete(t *testing.T) {
+	l := NewLRU(0, 0)
+	l.Set("key", []byte("old"), 0)
+	l.Set("key", []byte("new value"), 0)
+
+	value, ok, _ := l.Get("key")
+	if !ok || string(value) != "new value" {
+		t.Errorf("Get = %q, %v, want the replaced value", value, ok)
+	}
+	if l.bytes != int64(len("new value")) {
+		t.Errorf("bytes = %d, want %d", l.bytes, len("new value"))
+	}
+
+	l.Delete("key", "missing")
+	if _, ok, _ := l.Get("key"); ok {
+		t.Error("deleted value was kept")
+	}
+	if l.bytes != 0 || l.order.Len() != 0 {
+		t.Errorf("bytes = %d, entries = %d after deleting everything", l.bytes, l.order.Len())
+	}
+}
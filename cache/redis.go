@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type RedisConfig struct {
+	Addr     string        `yaml:"addr"`
+	Password string        `yaml:"password"`
+	DB       int           `yaml:"db"`
+	Prefix   string        `yaml:"prefix"`
+	PoolSize int           `yaml:"pool_size"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Backoff  time.Duration `yaml:"backoff"`
+}
+
+// defaultBackoff is how long a failed connection stops new ones when no
+// backoff is configured.
+const defaultBackoff = 2 * time.Second
+
+// errRedisDown is returned without dialing while backing off a failed connection.
+var errRedisDown = errors.New("redis: unavailable, backing off")
+
+// Redis keeps the values in any server speaking the Redis protocol, shared by
+// every instance. Its size is bounded by the server maxmemory policy. After a
+// failed connection, lookups fail fast for the backoff instead of redialing.
+type Redis struct {
+	cfg       *RedisConfig
+	pool      chan *redisConn
+	downUntil atomic.Int64
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// redisError is an error reply, the connection is still usable after it.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func NewRedis(cfg *RedisConfig) *Redis {
+	size := cfg.PoolSize
+	if size <= 0 {
+		size = 10
+	}
+	return &Redis{
+		cfg:  cfg,
+		pool: make(chan *redisConn, size),
+	}
+}
+
+func (r *Redis) Get(key string) ([]byte, bool, error) {
+	reply, err := r.do("GET", r.cfg.Prefix+key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %v", reply)
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) error {
+	args := []interface{}{"SET", r.cfg.Prefix + key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := r.do(args...)
+	return err
+}
+
+func (r *Redis) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := []interface{}{"DEL"}
+	for _, key := range keys {
+		args = append(args, r.cfg.Prefix+key)
+	}
+	_, err := r.do(args...)
+	return err
+}
+
+// do runs a command on a pooled connection, dropping the connection when it
+// fails for anything other than an error reply.
+func (r *Redis) do(args ...interface{}) (interface{}, error) {
+	c, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.do(r.cfg.Timeout, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.conn.Close()
+		return nil, err
+	}
+
+	select {
+	case r.pool <- c:
+	default:
+		c.conn.Close()
+	}
+	return reply, err
+}
+
+func (r *Redis) conn() (*redisConn, error) {
+	select {
+	case c := <-r.pool:
+		return c, nil
+	default:
+	}
+
+	if time.Now().UnixNano() < r.downUntil.Load() {
+		return nil, errRedisDown
+	}
+	c, err := r.dial()
+	if err != nil {
+		backoff := r.cfg.Backoff
+		if backoff <= 0 {
+			backoff = defaultBackoff
+		}
+		r.downUntil.Store(time.Now().Add(backoff).UnixNano())
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *Redis) dial() (*redisConn, error) {
+	conn, err := net.DialTimeout("tcp", r.cfg.Addr, r.cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+
+	if r.cfg.Password != "" {
+		_, err = c.do(r.cfg.Timeout, "AUTH", r.cfg.Password)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if r.cfg.DB != 0 {
+		_, err = c.do(r.cfg.Timeout, "SELECT", strconv.Itoa(r.cfg.DB))
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *redisConn) do(timeout time.Duration, args ...interface{}) (interface{}, error) {
+	if timeout > 0 {
+		err := c.conn.SetDeadline(time.Now().Add(timeout))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := c.conn.Write(encodeCommand(args))
+	if err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// encodeCommand writes the arguments as an array of bulk strings.
+func encodeCommand(args []interface{}) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		var value []byte
+		switch v := arg.(type) {
+		case []byte:
+			value = v
+		case string:
+			value = []byte(v)
+		}
+		buf = append(buf, "$"+strconv.Itoa(len(value))+"\r\n"...)
+		buf = append(buf, value...)
+		buf = append(buf, "\r\n"...)
+	}
+	return buf
+}
+
+// readReply reads a reply, nil bulk strings and arrays come as nil.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		_, err = io.ReadFull(r, buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		result := make([]interface{}, n)
+		for i := range result {
+			result[i], err = readReply(r)
+			var replyErr redisError
+			if errors.As(err, &replyErr) {
+				result[i] = replyErr
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []interface{}
+		want string
+	}{
+		{"strings", []interface{}{"GET", "user:a"}, "*2\r\n$3\r\nGET\r\n$6\r\nuser:a\r\n"},
+		{"bytes", []interface{}{"SET", "k", []byte("v\r\n")}, "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$3\r\nv\r\n\r\n"},
+		{"empty value", []interface{}{"SET", "k", []byte{}}, "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$0\r\n\r\n"},
+		{"utf-8 length in bytes", []interface{}{"GET", "são"}, "*2\r\n$3\r\nGET\r\n$4\r\nsão\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(encodeCommand(tt.args)); got != tt.want {
+				t.Errorf("encodeCommand(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    interface{}
+		wantErr error
+	}{
+		{"simple string", "+OK\r\n", "OK", nil},
+		{"error", "-ERR wrong type\r\n", nil, redisError("ERR wrong type")},
+		{"integer", ":42\r\n", int64(42), nil},
+		{"bulk string", "$5\r\nhello\r\n", []byte("hello"), nil},
+		{"bulk string with CRLF", "$4\r\na\r\nb\r\n", []byte("a\r\nb"), nil},
+		{"empty bulk string", "$0\r\n\r\n", []byte{}, nil},
+		{"nil bulk string", "$-1\r\n", nil, nil},
+		{"nil array", "*-1\r\n", nil, nil},
+		{
+			"array",
+			"*3\r\n$1\r\na\r\n$-1\r\n:1\r\n",
+			[]interface{}{[]byte("a"), nil, int64(1)},
+			nil,
+		},
+		{
+			"array with an error",
+			"*2\r\n+OK\r\n-ERR no\r\n",
+			[]interface{}{"OK", redisError("ERR no")},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readReply(bufio.NewReader(strings.NewReader(tt.reply)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readReply(%q) error = %v, want %v", tt.reply, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readReply(%q) = %#v, want %#v", tt.reply, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadReplyMalformed(t *testing.T) {
+	tests := []string{
+		"",
+		"\r\n",
+		"?what\r\n",
+		":abc\r\n",
+		"$5\r\nhi\r\n",
+		"*2\r\n+OK\r\n",
+	}
+	for _, reply := range tests {
+		t.Run(reply, func(t *testing.T) {
+			if _, err := readReply(bufio.NewReader(strings.NewReader(reply))); err == nil {
+				t.Errorf("readReply(%q) didn't fail", reply)
+			}
+		})
+	}
+}
+
+// fakeRedis answers GET, SET and DEL from a map, one connection at a time.
+func fakeRedis(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	values := map[string][]byte{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			for {
+				reply, err := readReply(r)
+				if err != nil {
+					conn.Close()
+					break
+				}
+				args := reply.([]interface{})
+				switch string(args[0].([]byte)) {
+				case "GET":
+					value, ok := values[string(args[1].([]byte))]
+					if !ok {
+						conn.Write([]byte("$-1\r\n"))
+						continue
+					}
+					conn.Write(encodeCommand([]interface{}{value})[4:])
+				case "SET":
+					values[string(args[1].([]byte))] = args[2].([]byte)
+					conn.Write([]byte("+OK\r\n"))
+				case "DEL":
+					delete(values, string(args[1].([]byte)))
+					conn.Write([]byte(":1\r\n"))
+				default:
+					conn.Write([]byte("-ERR unknown command\r\n"))
+				}
+			}
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestRedis(t *testing.T) {
+	r := NewRedis(&RedisConfig{Addr: fakeRedis(t), Prefix: "user:", Timeout: time.Second})
+
+	if _, ok, err := r.Get("missing"); ok || err != nil {
+		t.Fatalf("Get(missing) = %v, %v, want a miss", ok, err)
+	}
+	if err := r.Set("key", []byte("value"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	value, ok, err := r.Get("key")
+	if err != nil || !ok || string(value) != "value" {
+		t.Fatalf("Get(key) = %q, %v, %v, want the stored value", value, ok, err)
+	}
+	if err := r.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := r.Get("key"); ok {
+		t.Error("deleted key was found")
+	}
+}
+
+func TestRedisBacksOffAfterAFailedDial(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	r := NewRedis(&RedisConfig{Addr: addr, Timeout: time.Second, Backoff: time.Minute})
+	if _, _, err := r.Get("key"); err == nil || errors.Is(err, errRedisDown) {
+		t.Fatalf("first Get error = %v, want the dial error", err)
+	}
+	if _, _, err := r.Get("key"); !errors.Is(err, errRedisDown) {
+		t.Errorf("second Get error = %v, want %v", err, errRedisDown)
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	pb "github.com/ReStorePUC/protobucket/user"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/restore/user/cache"
 	"github.com/restore/user/config"
 	"github.com/restore/user/controller"
 	"github.com/restore/user/handler"
@@ -12,21 +14,42 @@ import (
 	"google.golang.org/grpc"
 	"log"
 	"net"
+	"time"
+	_ "time/tzdata"
 )
 
 func main() {
 	config.Init()
 	kgCfg := config.NewKongConfig()
 	dbCfg := config.NewDBConfig()
+	stCfg := config.NewStoreConfig()
+	ptCfg := config.NewPostalConfig()
+	smsCfg := config.NewSMSConfig()
+	geoCfg := config.NewGeocoderConfig()
+	pfCfg := config.NewProfanityConfig()
+	mailCfg := config.NewMailConfig()
+	avCfg := config.NewAvatarConfig()
+	cacheCfg := config.NewCacheConfig()
 
 	db, err := repository.Init(dbCfg)
 	if err != nil {
 		panic(err)
 	}
 
-	uRepo := repository.NewUser(db)
+	uRepo := repository.NewCachedUser(repository.NewUser(db), cache.New(cacheCfg), cacheCfg)
 	kong := service.NewKong(kgCfg)
-	uController := controller.NewUser(uRepo, kong)
+	storage := service.NewStorage()
+	postal := service.NewPostalLookup(ptCfg)
+	sms := service.NewSMS(smsCfg)
+	geocoder := service.NewNominatim(geoCfg)
+	profanity := service.NewWordList(pfCfg)
+	mailer := service.NewMailer(mailCfg)
+	if _, disabled := mailer.(service.DisabledMailer); disabled {
+		log.Printf("mail isn't configured, store owners won't be notified")
+	}
+	avatar := service.NewAvatar(avCfg)
+	suggester := service.NewPrefixIndex()
+	uController := controller.NewUser(uRepo, kong, storage, postal, sms, geocoder, profanity, mailer, avatar, suggester, stCfg, smsCfg)
 	uHandler := handler.NewUser(uController)
 	fHandler := handler.NewFile()
 
@@ -37,13 +60,38 @@ func main() {
 			log.Fatalf("failed to listen: %v", err)
 		}
 		s := grpc.NewServer()
-		pb.RegisterUserServer(s, handler.NewUserServer(uController))
+		uServer := handler.NewUserServer(uController)
+		pb.RegisterUserServer(s, uServer)
 		log.Printf("server listening at %v", lis.Addr())
 		if err := s.Serve(lis); err != nil {
 			log.Fatalf("failed to serve: %v", err)
 		}
 	}()
 
+	// Jobs
+	if err := uController.RefreshSlugs(context.Background()); err != nil {
+		log.Printf("failed to refresh store slugs: %v", err)
+	}
+	if err := uController.LoadSuggestions(context.Background()); err != nil {
+		log.Printf("failed to load store suggestions: %v", err)
+	}
+	go func() {
+		if err := uController.RefreshSearchText(context.Background()); err != nil {
+			log.Printf("failed to refresh search text: %v", err)
+		}
+	}()
+	if stCfg.PurgeInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(stCfg.PurgeInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := uController.PurgeArchivedStores(context.Background()); err != nil {
+					log.Printf("failed to purge archived stores: %v", err)
+				}
+			}
+		}()
+	}
+
 	// HTTP
 	router := gin.Default()
 	router.Use(cors.New(cors.Config{
@@ -61,15 +109,58 @@ func main() {
 	router.Static("/view-file/", "./uploads")
 	router.DELETE("/file/:file", fHandler.DeleteFile)
 	router.GET("/store/search/:name", uHandler.SearchStore)
-	router.GET("/store/admin/search", uHandler.SearchAdminStore)
+	router.GET("/store/nearby", uHandler.NearbyStores)
+	router.GET("/store/suggest", uHandler.SuggestStores)
+	router.GET("/store/search", uHandler.FilterStore)
+	router.GET("/categories", uHandler.GetCategories)
+	router.GET("/store/:id/reviews", uHandler.GetReviews)
+	router.GET("/address/lookup/:cep", uHandler.LookupAddress)
 
 	router.POST("/private/store", uHandler.RegisterStore)
+	router.PUT("/private/store/:id", uHandler.UpdateStore)
+	router.PUT("/private/store/:id/schedule", uHandler.UpdateSchedule)
+	router.PUT("/private/store/:id/review", uHandler.SaveReview)
+	router.PUT("/private/store/:id/reviews/:reviewID/reply", uHandler.ReplyReview)
+	router.DELETE("/private/store/:id", uHandler.DeleteStore)
+	router.POST("/private/store/:id/restore", uHandler.RestoreStore)
+	router.POST("/private/store/:id/resubmit", uHandler.ResubmitStore)
+	router.POST("/private/store/:id/members", uHandler.InviteMember)
+	router.GET("/private/store/:id/members", uHandler.GetMembers)
+	router.DELETE("/private/store/:id/members/:userID", uHandler.RemoveMember)
 	router.GET("/store/:id", uHandler.GetStore)
+	router.GET("/store/by-slug/:slug", uHandler.GetStoreBySlug)
 	router.GET("/private/profile/:id", uHandler.GetProfile)
 	router.PUT("/private/profile/:id", uHandler.UpdateProfile)
+	router.PATCH("/private/profile/:id", uHandler.PatchProfile)
+
+	router.POST("/private/admin/categories", uHandler.CreateCategory)
+	router.PUT("/private/admin/categories/:id", uHandler.UpdateCategory)
+	router.DELETE("/private/admin/categories/:id", uHandler.DeleteCategory)
+	router.POST("/private/admin/reviews/:id/hide", uHandler.HideReview)
+	router.POST("/private/admin/reviews/:id/show", uHandler.ShowReview)
+	router.GET("/private/admin/users", uHandler.SearchUsers)
+	router.GET("/private/admin/stores/search", uHandler.SearchAdminStore)
+	router.GET("/private/admin/stores/review-queue", uHandler.GetReviewQueue)
+	router.POST("/private/admin/stores/:id/approve", uHandler.ApproveStore)
+	router.POST("/private/admin/stores/:id/reject", uHandler.RejectStore)
 
 	router.GET("/private/self/store", uHandler.GetSelfStore)
 	router.GET("/private/self/profile", uHandler.GetSelfProfile)
+	router.PUT("/private/self/avatar", uHandler.UpdateSelfAvatar)
+	router.DELETE("/private/self/avatar", uHandler.DeleteSelfAvatar)
+	router.GET("/private/self/preferences", uHandler.GetSelfPreferences)
+	router.PATCH("/private/self/preferences", uHandler.UpdateSelfPreferences)
+	router.GET("/private/self/addresses", uHandler.GetSelfAddresses)
+	router.POST("/private/self/addresses", uHandler.CreateSelfAddress)
+	router.PUT("/private/self/addresses/:id", uHandler.UpdateSelfAddress)
+	router.DELETE("/private/self/addresses/:id", uHandler.DeleteSelfAddress)
+	router.POST("/private/self/phone/code", uHandler.SendPhoneCode)
+	router.POST("/private/self/phone/confirm", uHandler.ConfirmPhoneCode)
+	router.GET("/private/self/favorites", uHandler.GetFavorites)
+	router.PUT("/private/self/favorites/:storeID", uHandler.AddFavorite)
+	router.DELETE("/private/self/favorites/:storeID", uHandler.RemoveFavorite)
+
+	router.GET("/private/debug/vars", uHandler.DebugVars)
 
 	router.Run(":8080")
 }
@@ -1,18 +1,33 @@
 package config
 
 import (
+	"github.com/restore/user/cache"
 	"github.com/restore/user/repository"
 	"github.com/restore/user/service"
 	"gopkg.in/yaml.v3"
 	"log"
 	"os"
+	"time"
 )
 
 const EmailHeader = "X-Consumer-Username"
 
+type StoreConfig struct {
+	RetentionDays int           `yaml:"retention_days"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
 type Configuration struct {
-	Kong  service.KongConfig `yaml:"kong"`
-	Mysql repository.Config  `yaml:"mysql"`
+	Kong      service.KongConfig      `yaml:"kong"`
+	Mysql     repository.Config       `yaml:"mysql"`
+	Store     StoreConfig             `yaml:"store"`
+	Postal    service.PostalConfig    `yaml:"postal"`
+	SMS       service.SMSConfig       `yaml:"sms"`
+	Geocoder  service.GeocoderConfig  `yaml:"geocoder"`
+	Profanity service.ProfanityConfig `yaml:"profanity"`
+	Mail      service.MailConfig      `yaml:"mail"`
+	Avatar    service.AvatarConfig    `yaml:"avatar"`
+	Cache     cache.Config            `yaml:"cache"`
 }
 
 var config Configuration
@@ -38,3 +53,35 @@ func NewKongConfig() *service.KongConfig {
 func NewDBConfig() *repository.Config {
 	return &config.Mysql
 }
+
+func NewStoreConfig() *StoreConfig {
+	return &config.Store
+}
+
+func NewPostalConfig() *service.PostalConfig {
+	return &config.Postal
+}
+
+func NewSMSConfig() *service.SMSConfig {
+	return &config.SMS
+}
+
+func NewGeocoderConfig() *service.GeocoderConfig {
+	return &config.Geocoder
+}
+
+func NewProfanityConfig() *service.ProfanityConfig {
+	return &config.Profanity
+}
+
+func NewMailConfig() *service.MailConfig {
+	return &config.Mail
+}
+
+func NewAvatarConfig() *service.AvatarConfig {
+	return &config.Avatar
+}
+
+func NewCacheConfig() *cache.Config {
+	return &config.Cache
+}
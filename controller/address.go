@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// GetSelfAddresses lists the user address book.
+func (u *User) GetSelfAddresses(ctx context.Context) ([]entity.Address, error) {
+	log := zap.NewNop()
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.repo.GetAddresses(ctx, user.ID)
+	if err != nil {
+		log.Error(
+			"error getting addresses",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateSelfAddress adds an address to the user address book.
+func (u *User) CreateSelfAddress(ctx context.Context, address *entity.Address) error {
+	log := zap.NewNop()
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = u.normalizeAddress(ctx, &address.ZipCode, &address.City, &address.State)
+	if err != nil {
+		log.Error(
+			"error normalizing address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	address.ID = 0
+	address.UserID = user.ID
+	err = u.repo.CreateAddress(ctx, address)
+	if err != nil {
+		log.Error(
+			"error to create address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateSelfAddress replaces an address of the user address book.
+func (u *User) UpdateSelfAddress(ctx context.Context, id string, address *entity.Address) error {
+	log := zap.NewNop()
+
+	addressID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = u.normalizeAddress(ctx, &address.ZipCode, &address.City, &address.State)
+	if err != nil {
+		log.Error(
+			"error normalizing address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	address.ID = addressID
+	address.UserID = user.ID
+	err = u.repo.UpdateAddress(ctx, address)
+	if err != nil {
+		log.Error(
+			"error to update address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSelfAddress removes an address from the user address book.
+func (u *User) DeleteSelfAddress(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	addressID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = u.repo.DeleteAddress(ctx, user.ID, addressID)
+	if err != nil {
+		log.Error(
+			"error to delete address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// GetDefaultAddress finds the default shipping address of an user.
+func (u *User) GetDefaultAddress(ctx context.Context, email string) (*entity.Address, error) {
+	return u.repo.GetDefaultAddress(ctx, email)
+}
+
+// LookupAddress finds the address of a CEP.
+func (u *User) LookupAddress(ctx context.Context, cep string) (*entity.PostalAddress, error) {
+	log := zap.NewNop()
+
+	code, err := normalize.CEP(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.postal.Lookup(ctx, code)
+	if err != nil {
+		log.Error(
+			"error looking up CEP",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	result.City = normalize.City(result.City)
+
+	return result, nil
+}
+
+// normalizeAddress rewrites the CEP to its 8 digits, the state to its UF code and
+// the city to its canonical name, taken from the postal lookup when the CEP is known.
+func (u *User) normalizeAddress(ctx context.Context, zipCode *string, city *string, state *string) error {
+	log := zap.NewNop()
+
+	if *zipCode != "" {
+		code, err := normalize.CEP(*zipCode)
+		if err != nil {
+			return err
+		}
+		*zipCode = code
+
+		found, err := u.postal.Lookup(ctx, code)
+		if err != nil {
+			log.Warn(
+				"error looking up CEP",
+				zap.Error(err),
+			)
+		} else {
+			if found.City != "" {
+				*city = found.City
+			}
+			if found.State != "" {
+				*state = found.State
+			}
+		}
+	}
+
+	if *state != "" {
+		uf, err := normalize.UF(*state)
+		if err != nil {
+			return err
+		}
+		*state = uf
+	}
+	*city = normalize.City(*city)
+
+	return nil
+}
+
+func (u *User) selfUser(ctx context.Context) (*entity.User, error) {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader)
+	user, err := u.repo.GetUserByEmail(ctx, email.(string))
+	if err != nil {
+		log.Error(
+			"error getting user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"go.uber.org/zap"
+)
+
+// RequireAdmin fails unless the logged user is an admin, for the endpoints
+// served outside the controller.
+func (u *User) RequireAdmin(ctx context.Context) error {
+	return u.requireAdmin(ctx)
+}
+
+// SearchUsers lists a page of users with their profiles and stores, allowed to admins.
+func (u *User) SearchUsers(ctx context.Context, filter *entity.UserFilter) (*entity.Page[entity.UserSummary], error) {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.State != "" {
+		filter.State, err = normalize.UF(filter.State)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if filter.City != "" {
+		filter.City = normalize.City(filter.City)
+	}
+
+	result, next, err := u.repo.SearchUsers(ctx, filter)
+	if err != nil {
+		log.Error(
+			"error searching users",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	for i := range result {
+		result[i].User.Password = ""
+		if result[i].Profile != nil {
+			u.fillAvatar(result[i].Profile)
+		}
+	}
+
+	return &entity.Page[entity.UserSummary]{
+		Items:      result,
+		NextCursor: next,
+	}, nil
+}
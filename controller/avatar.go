@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io"
+)
+
+// UpdateSelfAvatar replaces the avatar of the logged profile, removing the previous files.
+func (u *User) UpdateSelfAvatar(ctx context.Context, r io.Reader) (*entity.Profile, error) {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader)
+	profile, err := u.repo.GetUserProfile(ctx, email.(string))
+	if err != nil {
+		log.Error(
+			"error getting profile",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	name, err := u.avatar.Save(r)
+	if err != nil {
+		log.Error(
+			"error saving avatar",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	err = u.repo.UpdateProfileAvatar(ctx, profile.ID, name)
+	if err != nil {
+		log.Error(
+			"error to update avatar",
+			zap.Error(err),
+		)
+		u.avatar.Delete(name)
+		return nil, err
+	}
+
+	err = u.avatar.Delete(profile.AvatarPath)
+	if err != nil {
+		log.Warn(
+			"error deleting previous avatar",
+			zap.Error(err),
+		)
+	}
+
+	profile.AvatarPath = name
+	u.fillAvatar(profile)
+	u.maskProfileFor(ctx, profile)
+	return profile, nil
+}
+
+// DeleteSelfAvatar removes the avatar of the logged profile.
+func (u *User) DeleteSelfAvatar(ctx context.Context) error {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader)
+	profile, err := u.repo.GetUserProfile(ctx, email.(string))
+	if err != nil {
+		log.Error(
+			"error getting profile",
+			zap.Error(err),
+		)
+		return err
+	}
+	if profile.AvatarPath == "" {
+		return nil
+	}
+
+	err = u.repo.UpdateProfileAvatar(ctx, profile.ID, "")
+	if err != nil {
+		log.Error(
+			"error to update avatar",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.avatar.Delete(profile.AvatarPath)
+	if err != nil {
+		log.Warn(
+			"error deleting avatar",
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
+
+// GetUserDetails gets an user with its profile and avatar, users without a
+// profile, like store owners, get an empty one.
+func (u *User) GetUserDetails(ctx context.Context, email string) (*entity.Profile, error) {
+	log := zap.NewNop()
+
+	user, err := u.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		log.Error(
+			"error getting user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	profile, err := u.repo.GetUserProfile(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		profile, err = &entity.Profile{UserID: user.ID}, nil
+	}
+	if err != nil {
+		log.Error(
+			"error getting profile",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	profile.User = *user
+	profile.User.Password = ""
+	u.fillAvatar(profile)
+	u.maskProfileFor(ctx, profile)
+	return profile, nil
+}
+
+func (u *User) fillAvatar(profile *entity.Profile) {
+	profile.AvatarURL = u.avatar.URL(profile.AvatarPath)
+	profile.AvatarURLs = u.avatar.URLs(profile.AvatarPath)
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxTags         = 20
+	maxTagLength    = 30
+	maxCategoryName = 50
+)
+
+// GetCategories lists the store categories.
+func (u *User) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	log := zap.NewNop()
+
+	result, err := u.repo.GetCategories(ctx)
+	if err != nil {
+		log.Error(
+			"error getting categories",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateCategory creates a store category, allowed to admins.
+func (u *User) CreateCategory(ctx context.Context, category *entity.Category) error {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = normalizeCategory(category)
+	if err != nil {
+		return err
+	}
+
+	category.ID = 0
+	err = u.repo.CreateCategory(ctx, category)
+	if err != nil {
+		log.Error(
+			"error to create category",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCategory renames a store category, allowed to admins.
+func (u *User) UpdateCategory(ctx context.Context, id string, category *entity.Category) error {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = normalizeCategory(category)
+	if err != nil {
+		return err
+	}
+
+	category.ID = categoryID
+	err = u.repo.UpdateCategory(ctx, category)
+	if err != nil {
+		log.Error(
+			"error to update category",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCategory deletes a store category, allowed to admins.
+func (u *User) DeleteCategory(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	categoryID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.repo.DeleteCategory(ctx, categoryID)
+	if err != nil {
+		log.Error(
+			"error to delete category",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// classifyStore normalizes the store tags and resolves its categories.
+func (u *User) classifyStore(ctx context.Context, store *entity.Store) error {
+	log := zap.NewNop()
+
+	tags, err := normalizeTags(store.Tags)
+	if err != nil {
+		return err
+	}
+	store.Tags = tags
+
+	if len(store.Categories) == 0 {
+		store.Categories = []entity.Category{}
+		return nil
+	}
+
+	ids := make([]int, 0, len(store.Categories))
+	seen := map[int]bool{}
+	for _, c := range store.Categories {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			ids = append(ids, c.ID)
+		}
+	}
+
+	categories, err := u.repo.GetCategoriesByIDs(ctx, ids)
+	if err != nil {
+		log.Error(
+			"error getting categories",
+			zap.Error(err),
+		)
+		return err
+	}
+	if len(categories) != len(ids) {
+		return entity.ErrUnknownCategory
+	}
+	store.Categories = categories
+
+	return nil
+}
+
+// normalizeTags trims, lowers and deduplicates the tags.
+func normalizeTags(tags entity.Tags) (entity.Tags, error) {
+	result := entity.Tags{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" || len([]rune(tag)) > maxTagLength || strings.Contains(tag, ",") {
+			return nil, entity.ErrInvalidTag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxTags {
+		return nil, entity.ErrInvalidTag
+	}
+	return result, nil
+}
+
+func normalizeCategory(category *entity.Category) error {
+	category.Name = strings.Join(strings.Fields(category.Name), " ")
+	if category.Name == "" || len([]rune(category.Name)) > maxCategoryName {
+		return entity.ErrInvalidCategory
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+)
+
+// normalizeProfileDocument validates the profile CPF and phone, empty ones are cleared.
+func normalizeProfileDocument(profile *entity.Profile) error {
+	profile.PhoneVerified = false
+	if profile.Phone != "" {
+		phone, err := normalize.Phone(profile.Phone)
+		if err != nil {
+			return err
+		}
+		profile.Phone = phone
+	}
+
+	if profile.CPF == nil || *profile.CPF == "" {
+		profile.CPF = nil
+		return nil
+	}
+
+	cpf, err := normalize.CPF(*profile.CPF)
+	if err != nil {
+		return err
+	}
+	profile.CPF = &cpf
+	return nil
+}
+
+// normalizeStoreDocuments validates the store phone, CNPJ and owner CPF, one
+// of the documents is required.
+func normalizeStoreDocuments(store *entity.Store) error {
+	store.PhoneVerified = false
+	if store.Phone != "" {
+		phone, err := normalize.Phone(store.Phone)
+		if err != nil {
+			return err
+		}
+		store.Phone = phone
+	}
+
+	if store.CNPJ != nil && *store.CNPJ == "" {
+		store.CNPJ = nil
+	}
+	if store.CNPJ == nil && store.CPF == "" {
+		return entity.ErrDocumentRequired
+	}
+
+	if store.CNPJ != nil {
+		cnpj, err := normalize.CNPJ(*store.CNPJ)
+		if err != nil {
+			return err
+		}
+		store.CNPJ = &cnpj
+	}
+	if store.CPF != "" {
+		cpf, err := normalize.CPF(store.CPF)
+		if err != nil {
+			return err
+		}
+		store.CPF = cpf
+	}
+	return nil
+}
+
+// maskProfileFor masks the profile CPF unless the caller can see it.
+func (u *User) maskProfileFor(ctx context.Context, profile *entity.Profile) {
+	if !u.canSeeProfileDocuments(ctx, profile) {
+		maskProfile(profile)
+	}
+}
+
+func maskProfile(profile *entity.Profile) {
+	if profile.CPF != nil {
+		masked := normalize.MaskCPF(*profile.CPF)
+		profile.CPF = &masked
+	}
+}
+
+func maskStore(store *entity.Store) {
+	if store.CNPJ != nil {
+		masked := normalize.MaskCNPJ(*store.CNPJ)
+		store.CNPJ = &masked
+	}
+	store.CPF = normalize.MaskCPF(store.CPF)
+}
+
+// canSeeProfileDocuments tells whether the caller owns the profile or is an admin.
+func (u *User) canSeeProfileDocuments(ctx context.Context, profile *entity.Profile) bool {
+	email, _ := ctx.Value(config.EmailHeader).(string)
+	if email == "" {
+		return false
+	}
+
+	user, err := u.repo.GetUserByEmail(ctx, email)
+	return err == nil && (user.ID == profile.UserID || user.IsAdmin)
+}
+
+// isAdmin tells whether the caller is an admin.
+func (u *User) isAdmin(ctx context.Context) bool {
+	email, _ := ctx.Value(config.EmailHeader).(string)
+	if email == "" {
+		return false
+	}
+
+	user, err := u.repo.GetUserByEmail(ctx, email)
+	return err == nil && user.IsAdmin
+}
+
+// canSeeDocuments tells whether the caller is the store owner or an admin.
+func (u *User) canSeeDocuments(ctx context.Context, storeID int) bool {
+	email, _ := ctx.Value(config.EmailHeader).(string)
+	if email == "" {
+		return false
+	}
+
+	_, err := u.requireStoreRole(ctx, storeID, true, entity.RoleOwner)
+	return err == nil
+}
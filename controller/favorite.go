@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// AddFavorite makes the user follow a store.
+func (u *User) AddFavorite(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to get store",
+			zap.Error(err),
+		)
+		return err
+	}
+	if store.ArchivedAt.Valid {
+		return entity.ErrStoreArchived
+	}
+	if store.Status != entity.StatusApproved {
+		return gorm.ErrRecordNotFound
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	added, err := u.repo.AddFavorite(ctx, &entity.Favorite{
+		UserID:  user.ID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		log.Error(
+			"error to add favorite",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if added {
+		subject := fmt.Sprintf("Your store %s has a new follower", store.Name)
+		u.notify(ctx, store.UserID, store.User.Email, storePhone(store), entity.NotifyNewFollower, subject, subject)
+	}
+
+	return nil
+}
+
+// RemoveFavorite makes the user unfollow a store.
+func (u *User) RemoveFavorite(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = u.repo.DeleteFavorite(ctx, user.ID, storeID)
+	if err != nil {
+		log.Error(
+			"error to delete favorite",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// GetFavorites lists a page of the stores the user follows.
+func (u *User) GetFavorites(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Favorite], error) {
+	log := zap.NewNop()
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, next, err := u.repo.GetFavorites(ctx, user.ID, page)
+	if err != nil {
+		log.Error(
+			"error getting favorites",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	for i := range result {
+		if result[i].Store != nil {
+			maskStore(result[i].Store)
+		}
+	}
+
+	return &entity.Page[entity.Favorite]{
+		Items:      result,
+		NextCursor: next,
+	}, nil
+}
+
+// GetStoreFollowers lists the users following a store.
+func (u *User) GetStoreFollowers(ctx context.Context, id string) ([]entity.User, error) {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result, err := u.repo.GetFollowers(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error getting followers",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	for i := range result {
+		result[i].Password = ""
+	}
+
+	return result, nil
+}
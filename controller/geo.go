@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	earthRadiusKm   = 6371.0
+	kmPerDegree     = 111.32
+	defaultRadiusKm = 5.0
+	maxRadiusKm     = 100.0
+)
+
+// NearbyStores finds the stores inside the radius ordered by distance.
+func (u *User) NearbyStores(ctx context.Context, filter *entity.NearbyFilter) ([]entity.Store, error) {
+	log := zap.NewNop()
+
+	if filter.RadiusKm == 0 {
+		filter.RadiusKm = defaultRadiusKm
+	}
+	if math.IsNaN(filter.RadiusKm) || filter.RadiusKm < 0 || filter.RadiusKm > maxRadiusKm {
+		return nil, fmt.Errorf("%w: radius must be up to %.0f km", entity.ErrInvalidLocation, maxRadiusKm)
+	}
+	err := validateCoordinates(filter.Latitude, filter.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	dLat := filter.RadiusKm / kmPerDegree
+	dLng := filter.RadiusKm / (kmPerDegree * math.Max(math.Cos(filter.Latitude*math.Pi/180), 0.01))
+	stores, err := u.repo.NearbyStores(
+		ctx,
+		filter.Latitude-dLat,
+		filter.Latitude+dLat,
+		filter.Longitude-dLng,
+		filter.Longitude+dLng,
+	)
+	if err != nil {
+		log.Error(
+			"error to get nearby stores",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	now := time.Now()
+	result := stores[:0]
+	for i := range stores {
+		store := stores[i]
+		distance := haversine(filter.Latitude, filter.Longitude, *store.Latitude, *store.Longitude)
+		if distance > filter.RadiusKm {
+			continue
+		}
+		store.Distance = &distance
+		store.OpenNow = openNow(&store, now)
+		maskStore(&store)
+		result = append(result, store)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return *result[i].Distance < *result[j].Distance
+	})
+
+	return result, nil
+}
+
+// locateStore validates the store coordinates, a store without them is geocoded
+// from its address and left unlocated when the geocoder can't place it.
+func (u *User) locateStore(ctx context.Context, store *entity.Store) error {
+	log := zap.NewNop()
+
+	if store.Latitude != nil && store.Longitude != nil {
+		return validateCoordinates(*store.Latitude, *store.Longitude)
+	}
+	if store.Latitude != nil || store.Longitude != nil {
+		return fmt.Errorf("%w: latitude and longitude go together", entity.ErrInvalidLocation)
+	}
+
+	parts := []string{}
+	for _, part := range []string{store.Address, store.Block, store.City, store.State, store.ZipCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	lat, lng, err := u.geocoder.Geocode(ctx, strings.Join(parts, ", "))
+	if err != nil {
+		log.Warn(
+			"error geocoding store",
+			zap.Error(err),
+		)
+		return nil
+	}
+	store.Latitude = &lat
+	store.Longitude = &lng
+
+	return nil
+}
+
+func validateCoordinates(lat float64, lng float64) error {
+	if math.IsNaN(lat) || math.IsNaN(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return fmt.Errorf("%w: coordinates out of range", entity.ErrInvalidLocation)
+	}
+	return nil
+}
+
+// haversine computes the great-circle distance in km between two points.
+func haversine(lat1 float64, lng1 float64, lat2 float64, lng2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLng := (lng2 - lng1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// InviteMember adds an existing user to a store with the given role.
+func (u *User) InviteMember(ctx context.Context, id string, invite *entity.MemberInvite) (*entity.StoreMember, error) {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if invite.Role != entity.RoleManager && invite.Role != entity.RoleStaff {
+		return nil, entity.ErrInvalidRole
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, false, entity.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.repo.GetUserByEmail(ctx, invite.Email)
+	if err != nil {
+		log.Error(
+			"error getting invited user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	member := &entity.StoreMember{
+		StoreID: storeID,
+		UserID:  user.ID,
+		Role:    invite.Role,
+	}
+	err = u.repo.CreateMember(ctx, member)
+	if err != nil {
+		log.Error(
+			"error to create member",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	user.Password = ""
+	member.User = *user
+	return member, nil
+}
+
+// GetMembers lists the members of a store.
+func (u *User) GetMembers(ctx context.Context, id string) ([]entity.StoreMember, error) {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, false, entity.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := u.repo.GetMembers(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to get members",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	for i := range members {
+		members[i].User.Password = ""
+	}
+
+	return members, nil
+}
+
+// RemoveMember removes an user from a store, the owner can't be removed.
+func (u *User) RemoveMember(ctx context.Context, id string, userID string) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	memberID, err := strconv.Atoi(userID)
+	if err != nil {
+		log.Error(
+			"error validating user id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, false, entity.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	member, err := u.repo.GetMember(ctx, storeID, memberID)
+	if err != nil {
+		log.Error(
+			"error to get member",
+			zap.Error(err),
+		)
+		return err
+	}
+	if member.Role == entity.RoleOwner {
+		return entity.ErrOwnerRemoval
+	}
+
+	err = u.repo.DeleteMember(ctx, storeID, memberID)
+	if err != nil {
+		log.Error(
+			"error to delete member",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// requireStoreRole checks that the caller has one of the roles on the store,
+// admins are let through when allowAdmin is set.
+func (u *User) requireStoreRole(ctx context.Context, storeID int, allowAdmin bool, roles ...string) (*entity.User, error) {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader)
+	user, err := u.repo.GetUserByEmail(ctx, email.(string))
+	if err != nil {
+		log.Error(
+			"error getting user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if allowAdmin && user.IsAdmin {
+		return user, nil
+	}
+
+	member, err := u.repo.GetMember(ctx, storeID, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.ErrUnauthorized
+	}
+	if err != nil {
+		log.Error(
+			"error getting member",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	for _, role := range roles {
+		if member.Role == role {
+			return user, nil
+		}
+	}
+
+	log.Error(
+		"unauthorized action",
+	)
+	return nil, entity.ErrUnauthorized
+}
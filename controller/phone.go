@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"math/big"
+	"time"
+)
+
+const maxPhoneAttempts = 5
+
+// SendPhoneCode sends a verification code to a phone of the user profile or
+// of a store the user owns or manages. While a code is valid, new ones wait for
+// the resend interval, up to the max sends, and keep the failed attempts.
+func (u *User) SendPhoneCode(ctx context.Context, phone string) error {
+	log := zap.NewNop()
+
+	number, err := normalize.Phone(phone)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	owned, err := u.ownsPhone(ctx, number)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return entity.ErrUnauthorized
+	}
+
+	now := time.Now()
+	verification := &entity.PhoneVerification{
+		UserID:    user.ID,
+		Phone:     number,
+		Sends:     1,
+		SentAt:    now,
+		ExpiresAt: now.Add(u.smsCfg.CodeTTL),
+	}
+	current, err := u.repo.GetPhoneVerification(ctx, user.ID, number)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(
+			"error getting phone verification",
+			zap.Error(err),
+		)
+		return err
+	}
+	if err == nil && now.Before(current.ExpiresAt) {
+		if now.Sub(current.SentAt) < u.smsCfg.ResendInterval || current.Sends >= u.smsCfg.MaxSends {
+			return entity.ErrTooManyCodes
+		}
+		verification.Attempts = current.Attempts
+		verification.Sends = current.Sends + 1
+	}
+
+	code, err := verificationCode()
+	if err != nil {
+		log.Error(
+			"error generating code",
+			zap.Error(err),
+		)
+		return err
+	}
+	hash, err := crypt(code)
+	if err != nil {
+		log.Error(
+			"error to crypt code",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	verification.Code = hash
+	err = u.repo.CreatePhoneVerification(ctx, verification)
+	if err != nil {
+		log.Error(
+			"error to create phone verification",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.sms.Send(number, fmt.Sprintf("Your ReStore verification code is %s", code))
+	if err != nil {
+		log.Error(
+			"error sending sms",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ConfirmPhoneCode checks a verification code and marks its phone verified.
+func (u *User) ConfirmPhoneCode(ctx context.Context, code *entity.PhoneCode) error {
+	log := zap.NewNop()
+
+	number, err := normalize.Phone(code.Phone)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	verification, err := u.repo.GetPhoneVerification(ctx, user.ID, number)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ErrInvalidCode
+	}
+	if err != nil {
+		log.Error(
+			"error getting phone verification",
+			zap.Error(err),
+		)
+		return err
+	}
+	if verification.Attempts >= maxPhoneAttempts || time.Now().After(verification.ExpiresAt) {
+		return entity.ErrInvalidCode
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(verification.Code), []byte(code.Code))
+	if err != nil {
+		err = u.repo.IncrementPhoneAttempts(ctx, verification.ID)
+		if err != nil {
+			log.Error(
+				"error counting phone attempt",
+				zap.Error(err),
+			)
+			return err
+		}
+		return entity.ErrInvalidCode
+	}
+
+	err = u.repo.ConfirmPhone(ctx, user.ID, number)
+	if err != nil {
+		log.Error(
+			"error confirming phone",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ownsPhone tells whether the phone belongs to the user profile or to a store
+// the user owns or manages.
+func (u *User) ownsPhone(ctx context.Context, phone string) (bool, error) {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader).(string)
+	profile, err := u.repo.GetUserProfile(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(
+			"error getting profile",
+			zap.Error(err),
+		)
+		return false, err
+	}
+	if profile != nil && profile.Phone == phone {
+		return true, nil
+	}
+
+	stores, err := u.repo.GetUserStores(ctx, email)
+	if err != nil {
+		log.Error(
+			"error getting stores",
+			zap.Error(err),
+		)
+		return false, err
+	}
+	for _, s := range stores {
+		if s.Role != entity.RoleStaff && s.Store != nil && s.Store.Phone == phone {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func verificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"github.com/restore/user/service"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+const testPhone = "+5511999998888"
+
+// phoneRepo keeps the phone verification of a single user owning testPhone.
+type phoneRepo struct {
+	repository
+	verification *entity.PhoneVerification
+	confirmed    bool
+}
+
+func (r *phoneRepo) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return &entity.User{ID: 1, Email: email}, nil
+}
+
+func (r *phoneRepo) GetUserProfile(ctx context.Context, email string) (*entity.Profile, error) {
+	return &entity.Profile{Phone: testPhone}, nil
+}
+
+func (r *phoneRepo) GetUserStores(ctx context.Context, email string) ([]entity.StoreMember, error) {
+	return nil, nil
+}
+
+func (r *phoneRepo) GetPhoneVerification(ctx context.Context, userID int, phone string) (*entity.PhoneVerification, error) {
+	if r.verification == nil || r.verification.Phone != phone {
+		return nil, gorm.ErrRecordNotFound
+	}
+	verification := *r.verification
+	return &verification, nil
+}
+
+func (r *phoneRepo) CreatePhoneVerification(ctx context.Context, verification *entity.PhoneVerification) error {
+	r.verification = verification
+	return nil
+}
+
+func (r *phoneRepo) IncrementPhoneAttempts(ctx context.Context, id int) error {
+	r.verification.Attempts++
+	return nil
+}
+
+func (r *phoneRepo) ConfirmPhone(ctx context.Context, userID int, phone string) error {
+	r.confirmed = true
+	return nil
+}
+
+type sentSMS []string
+
+func (s *sentSMS) Send(phone string, message string) error {
+	*s = append(*s, phone)
+	return nil
+}
+
+func newPhoneUser(verification *entity.PhoneVerification) (*User, *phoneRepo, *sentSMS) {
+	repo := &phoneRepo{verification: verification}
+	sent := &sentSMS{}
+	return &User{
+		repo: repo,
+		sms:  sent,
+		smsCfg: &service.SMSConfig{
+			CodeTTL:        10 * time.Minute,
+			ResendInterval: time.Minute,
+			MaxSends:       3,
+		},
+	}, repo, sent
+}
+
+func TestSendPhoneCode(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		phone        string
+		current      *entity.PhoneVerification
+		wantErr      error
+		wantSends    int
+		wantAttempts int
+	}{
+		{"first code", "(11) 99999-8888", nil, nil, 1, 0},
+		{
+			"resend keeps the attempts",
+			testPhone,
+			&entity.PhoneVerification{Phone: testPhone, Sends: 1, Attempts: 2, SentAt: now.Add(-2 * time.Minute), ExpiresAt: now.Add(time.Minute)},
+			nil, 2, 2,
+		},
+		{
+			"resend too soon",
+			testPhone,
+			&entity.PhoneVerification{Phone: testPhone, Sends: 1, SentAt: now.Add(-30 * time.Second), ExpiresAt: now.Add(time.Minute)},
+			entity.ErrTooManyCodes, 1, 0,
+		},
+		{
+			"max sends",
+			testPhone,
+			&entity.PhoneVerification{Phone: testPhone, Sends: 3, SentAt: now.Add(-5 * time.Minute), ExpiresAt: now.Add(time.Minute)},
+			entity.ErrTooManyCodes, 3, 0,
+		},
+		{
+			"expired code starts over",
+			testPhone,
+			&entity.PhoneVerification{Phone: testPhone, Sends: 3, Attempts: 5, SentAt: now.Add(-20 * time.Minute), ExpiresAt: now.Add(-10 * time.Minute)},
+			nil, 1, 0,
+		},
+		{"phone of somebody else", "+5521988887777", nil, entity.ErrUnauthorized, 0, 0},
+		{"invalid phone", "9999-8888", nil, normalize.ErrInvalidPhone, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, repo, sent := newPhoneUser(tt.current)
+			ctx := context.WithValue(context.Background(), config.EmailHeader, "user@restore.com")
+
+			err := u.SendPhoneCode(ctx, tt.phone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SendPhoneCode(%q) error = %v, want %v", tt.phone, err, tt.wantErr)
+			}
+			wantSent := 0
+			if err == nil {
+				wantSent = 1
+			}
+			if len(*sent) != wantSent {
+				t.Errorf("%d messages sent, want %d", len(*sent), wantSent)
+			}
+			if repo.verification == nil {
+				if tt.wantSends != 0 {
+					t.Fatalf("no verification, want %d sends", tt.wantSends)
+				}
+				return
+			}
+			if repo.verification.Sends != tt.wantSends || repo.verification.Attempts != tt.wantAttempts {
+				t.Errorf("verification has %d sends and %d attempts, want %d and %d",
+					repo.verification.Sends, repo.verification.Attempts, tt.wantSends, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestConfirmPhoneCode(t *testing.T) {
+	hash, err := crypt("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := func(attempts int, expiresIn time.Duration) *entity.PhoneVerification {
+		return &entity.PhoneVerification{
+			ID:        1,
+			Phone:     testPhone,
+			Code:      hash,
+			Attempts:  attempts,
+			ExpiresAt: time.Now().Add(expiresIn),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		current       *entity.PhoneVerification
+		code          string
+		wantErr       error
+		wantAttempts  int
+		wantConfirmed bool
+	}{
+		{"right code", valid(0, time.Minute), "123456", nil, 0, true},
+		{"right code after failed attempts", valid(maxPhoneAttempts-1, time.Minute), "123456", nil, maxPhoneAttempts - 1, true},
+		{"wrong code", valid(0, time.Minute), "654321", entity.ErrInvalidCode, 1, false},
+		{"no attempts left", valid(maxPhoneAttempts, time.Minute), "123456", entity.ErrInvalidCode, maxPhoneAttempts, false},
+		{"expired code", valid(0, -time.Minute), "123456", entity.ErrInvalidCode, 0, false},
+		{"no code sent", nil, "123456", entity.ErrInvalidCode, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, repo, _ := newPhoneUser(tt.current)
+			ctx := context.WithValue(context.Background(), config.EmailHeader, "user@restore.com")
+
+			err := u.ConfirmPhoneCode(ctx, &entity.PhoneCode{Phone: "(11) 99999-8888", Code: tt.code})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConfirmPhoneCode error = %v, want %v", err, tt.wantErr)
+			}
+			if repo.confirmed != tt.wantConfirmed {
+				t.Errorf("phone confirmed = %v, want %v", repo.confirmed, tt.wantConfirmed)
+			}
+			if repo.verification != nil && repo.verification.Attempts != tt.wantAttempts {
+				t.Errorf("%d attempts, want %d", repo.verification.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"time"
+)
+
+const defaultLocale = "pt-BR"
+
+// GetSelfPreferences gets the preferences of the logged user.
+func (u *User) GetSelfPreferences(ctx context.Context) (*entity.Preferences, error) {
+	email := ctx.Value(config.EmailHeader)
+	return u.GetUserPreferences(ctx, email.(string))
+}
+
+// GetUserPreferences gets the preferences of an user, filled with the defaults
+// for whatever wasn't set yet.
+func (u *User) GetUserPreferences(ctx context.Context, email string) (*entity.Preferences, error) {
+	log := zap.NewNop()
+
+	user, err := u.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		log.Error(
+			"error getting user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return u.preferences(ctx, user.ID)
+}
+
+// UpdateSelfPreferences changes the given preferences of the logged user.
+func (u *User) UpdateSelfPreferences(ctx context.Context, patch *entity.PreferencesPatch) (*entity.Preferences, error) {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader)
+	user, err := u.repo.GetUserByEmail(ctx, email.(string))
+	if err != nil {
+		log.Error(
+			"error getting user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	preferences, err := u.preferences(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyPreferences(preferences, patch)
+	if err != nil {
+		log.Error(
+			"error validating preferences",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	err = u.repo.SavePreferences(ctx, preferences)
+	if err != nil {
+		log.Error(
+			"error to save preferences",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// notify sends a notification through the channels the user enabled for its
+// type, SMS only goes to a verified phone, empty when there's none. Failures
+// are logged and don't fail the action that triggered the notification.
+func (u *User) notify(ctx context.Context, userID int, email string, phone string, kind string, subject string, message string) {
+	log := zap.NewNop()
+
+	channels := entity.NotificationChannels{Email: true}
+	preferences, err := u.preferences(ctx, userID)
+	if err == nil {
+		channels = preferences.Notifications[kind]
+	}
+
+	if channels.Email && email != "" {
+		err = u.notifier.Notify(email, subject, message)
+		if err != nil {
+			log.Warn(
+				"error sending notification email",
+				zap.String("type", kind),
+				zap.Error(err),
+			)
+		}
+	}
+	if channels.SMS && phone != "" {
+		err = u.sms.Send(phone, message)
+		if err != nil {
+			log.Warn(
+				"error sending notification sms",
+				zap.String("type", kind),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// storePhone is the phone store notifications go to, if verified.
+func storePhone(store *entity.Store) string {
+	if !store.PhoneVerified {
+		return ""
+	}
+	return store.Phone
+}
+
+func (u *User) preferences(ctx context.Context, userID int) (*entity.Preferences, error) {
+	log := zap.NewNop()
+
+	preferences, err := u.repo.GetPreferences(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preferences, err = &entity.Preferences{UserID: userID}, nil
+	}
+	if err != nil {
+		log.Error(
+			"error getting preferences",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	defaultPreferences(preferences)
+	return preferences, nil
+}
+
+// defaultPreferences fills the unset preferences, notifications go by email only.
+func defaultPreferences(preferences *entity.Preferences) {
+	if preferences.Locale == "" {
+		preferences.Locale = defaultLocale
+	}
+	if preferences.Timezone == "" {
+		preferences.Timezone = defaultTimezone
+	}
+	if preferences.Notifications == nil {
+		preferences.Notifications = entity.Notifications{}
+	}
+	for _, kind := range entity.NotificationTypes {
+		if _, ok := preferences.Notifications[kind]; !ok {
+			preferences.Notifications[kind] = entity.NotificationChannels{Email: true}
+		}
+	}
+}
+
+// applyPreferences validates and merges a patch into the preferences.
+func applyPreferences(preferences *entity.Preferences, patch *entity.PreferencesPatch) error {
+	if patch.Locale != nil {
+		tag, err := language.Parse(*patch.Locale)
+		if err != nil {
+			return fmt.Errorf("%w: unknown locale %q", entity.ErrInvalidPreferences, *patch.Locale)
+		}
+		preferences.Locale = tag.String()
+	}
+	if patch.Timezone != nil {
+		if _, err := time.LoadLocation(*patch.Timezone); err != nil || *patch.Timezone == "" {
+			return fmt.Errorf("%w: unknown timezone %q", entity.ErrInvalidPreferences, *patch.Timezone)
+		}
+		preferences.Timezone = *patch.Timezone
+	}
+	for kind, channels := range patch.Notifications {
+		current, ok := preferences.Notifications[kind]
+		if !ok {
+			return fmt.Errorf("%w: unknown notification type %q", entity.ErrInvalidPreferences, kind)
+		}
+		if channels.Email != nil {
+			current.Email = *channels.Email
+		}
+		if channels.SMS != nil {
+			current.SMS = *channels.SMS
+		}
+		preferences.Notifications[kind] = current
+	}
+	if patch.Marketing != nil {
+		preferences.Marketing = *patch.Marketing
+	}
+	return nil
+}
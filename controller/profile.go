@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// profilePatchFields are the profile fields a merge patch may change, the
+// rest, like `user_id` and `user`, are managed by the service.
+var profilePatchFields = map[string]bool{
+	"name":     true,
+	"cpf":      true,
+	"phone":    true,
+	"address":  true,
+	"block":    true,
+	"zip_code": true,
+	"city":     true,
+	"state":    true,
+}
+
+// PatchProfile applies a JSON Merge Patch (RFC 7396) to a profile still at the
+// given version, allowed to its owner and admins, fields missing from the patch are kept.
+func (u *User) PatchProfile(ctx context.Context, id string, version int, patch []byte) (*entity.Profile, error) {
+	log := zap.NewNop()
+
+	profileID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	var changes map[string]interface{}
+	err = json.Unmarshal(patch, &changes)
+	if err != nil || changes == nil {
+		return nil, fmt.Errorf("%w: the patch must be a JSON object", entity.ErrInvalidProfile)
+	}
+	for field := range changes {
+		if !profilePatchFields[field] {
+			return nil, fmt.Errorf("%w: field %q can't be changed", entity.ErrInvalidProfile, field)
+		}
+	}
+
+	current, err := u.requireProfileOwner(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && current.Version != version {
+		return nil, entity.ErrVersionMismatch
+	}
+
+	profile, err := mergeProfile(current, changes)
+	if err != nil {
+		log.Error(
+			"error merging profile",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	err = u.saveProfile(ctx, profileID, version, profile, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.repo.GetProfileByID(ctx, profileID)
+	if err != nil {
+		log.Error(
+			"error to get profile",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	u.fillAvatar(result)
+	u.maskProfileFor(ctx, result)
+
+	return result, nil
+}
+
+// requireProfileOwner gets a profile when the logged user owns it or is an admin.
+func (u *User) requireProfileOwner(ctx context.Context, profileID int) (*entity.Profile, error) {
+	log := zap.NewNop()
+
+	email := ctx.Value(config.EmailHeader)
+	user, err := u.repo.GetUserByEmail(ctx, email.(string))
+	if err != nil {
+		log.Error(
+			"error getting user",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	profile, err := u.repo.GetProfileByID(ctx, profileID)
+	if err != nil {
+		log.Error(
+			"error to get profile",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if profile.UserID != user.ID && !user.IsAdmin {
+		log.Error(
+			"unauthorized action",
+		)
+		return nil, entity.ErrUnauthorized
+	}
+
+	return profile, nil
+}
+
+// saveProfile normalizes, validates and stores every field of a profile, only
+// the changed fields are validated when given.
+func (u *User) saveProfile(ctx context.Context, profileID int, version int, profile *entity.Profile, changes map[string]interface{}) error {
+	log := zap.NewNop()
+
+	err := normalizeProfileDocument(profile)
+	if err != nil {
+		log.Error(
+			"error validating document",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.normalizeAddress(ctx, &profile.ZipCode, &profile.City, &profile.State)
+	if err != nil {
+		log.Error(
+			"error normalizing address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = validateProfile(profile, changes)
+	if err != nil {
+		log.Error(
+			"error validating profile",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.repo.UpdateProfile(ctx, profileID, version, profile)
+	if err != nil {
+		log.Error(
+			"error to update profile",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// validateProfile checks the fields a full profile must have, or only the
+// changed ones for a patch, so older profiles missing some can still be patched.
+func validateProfile(profile *entity.Profile, changes map[string]interface{}) error {
+	required := []struct {
+		field string
+		value string
+	}{
+		{"name", profile.Name},
+		{"address", profile.Address},
+		{"block", profile.Block},
+		{"zip_code", profile.ZipCode},
+		{"city", profile.City},
+		{"state", profile.State},
+	}
+	for _, r := range required {
+		if _, changed := changes[r.field]; changes != nil && !changed {
+			continue
+		}
+		if r.value == "" {
+			return fmt.Errorf("%w: %s is required", entity.ErrInvalidProfile, r.field)
+		}
+	}
+	return nil
+}
+
+// mergeProfile applies the patch to the JSON document of a profile.
+func mergeProfile(profile *entity.Profile, changes map[string]interface{}) (*entity.Profile, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(mergePatch(document, changes))
+	if err != nil {
+		return nil, err
+	}
+
+	var result entity.Profile
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidProfile, err)
+	}
+	return &result, nil
+}
+
+// mergePatch implements the RFC 7396 MergePatch algorithm, null members are
+// removed and objects are merged recursively.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	document, ok := target.(map[string]interface{})
+	if !ok {
+		document = map[string]interface{}{}
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(document, key)
+			continue
+		}
+		document[key] = mergePatch(document[key], value)
+	}
+	return document
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/restore/user/entity"
+	"reflect"
+	"testing"
+)
+
+// TestMergePatch runs the examples of RFC 7396, appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target string
+		patch  string
+		want   string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.patch, func(t *testing.T) {
+			var target, patch, want interface{}
+			for _, doc := range []struct {
+				text  string
+				value *interface{}
+			}{{tt.target, &target}, {tt.patch, &patch}, {tt.want, &want}} {
+				if err := json.Unmarshal([]byte(doc.text), doc.value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergePatch(%s, %s) = %v, want %s", tt.target, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchProfileRejectsFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{"not JSON", `{"name":`},
+		{"not an object", `["name"]`},
+		{"null", `null`},
+		{"user", `{"user_id":2}`},
+		{"version", `{"name":"Ana","version":9}`},
+		{"verified phone", `{"phone_verified":true}`},
+		{"avatar", `{"avatar_path":"other.png"}`},
+		{"unknown field", `{"nickname":"ana"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &User{}
+			_, err := u.PatchProfile(context.Background(), "1", 0, []byte(tt.patch))
+			if !errors.Is(err, entity.ErrInvalidProfile) {
+				t.Errorf("PatchProfile(%s) error = %v, want %v", tt.patch, err, entity.ErrInvalidProfile)
+			}
+		})
+	}
+}
+
+func TestMergeProfile(t *testing.T) {
+	current := &entity.Profile{
+		ID:      1,
+		UserID:  2,
+		Name:    "Ana",
+		Phone:   "+5511999998888",
+		Address: "Rua A, 1",
+		City:    "São Paulo",
+		State:   "SP",
+		Version: 3,
+	}
+	tests := []struct {
+		name    string
+		changes map[string]interface{}
+		want    func(p *entity.Profile)
+	}{
+		{"no changes", map[string]interface{}{}, func(p *entity.Profile) {}},
+		{"name", map[string]interface{}{"name": "Ana Maria"}, func(p *entity.Profile) { p.Name = "Ana Maria" }},
+		{"null clears", map[string]interface{}{"phone": nil}, func(p *entity.Profile) { p.Phone = "" }},
+		{
+			"several fields",
+			map[string]interface{}{"city": "Rio de Janeiro", "state": "RJ"},
+			func(p *entity.Profile) { p.City, p.State = "Rio de Janeiro", "RJ" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := *current
+			tt.want(&want)
+
+			got, err := mergeProfile(current, tt.changes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("mergeProfile(%v) = %+v, want %+v", tt.changes, *got, want)
+			}
+		})
+	}
+
+	_, err := mergeProfile(current, map[string]interface{}{"name": 42})
+	if !errors.Is(err, entity.ErrInvalidProfile) {
+		t.Errorf("mergeProfile with a number name error = %v, want %v", err, entity.ErrInvalidProfile)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	full := entity.Profile{Name: "Ana", Address: "Rua A, 1", Block: "Centro", ZipCode: "01001-000", City: "São Paulo", State: "SP"}
+	partial := entity.Profile{Name: "Ana"}
+	tests := []struct {
+		name    string
+		profile entity.Profile
+		changes map[string]interface{}
+		wantErr error
+	}{
+		{"full profile", full, nil, nil},
+		{"missing field", partial, nil, entity.ErrInvalidProfile},
+		{"patch of a partial profile", partial, map[string]interface{}{"name": "Ana"}, nil},
+		{"patch clearing a required field", partial, map[string]interface{}{"city": nil}, entity.ErrInvalidProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateProfile(&tt.profile, tt.changes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateProfile error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+const maxReviewLength = 2000
+
+// SaveReview creates or edits the caller review of a store, members can't review their own store.
+func (u *User) SaveReview(ctx context.Context, id string, review *entity.Review) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if review.Rating < 1 || review.Rating > 5 || len([]rune(review.Text)) > maxReviewLength {
+		return entity.ErrInvalidReview
+	}
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to get store",
+			zap.Error(err),
+		)
+		return err
+	}
+	if store.ArchivedAt.Valid {
+		return entity.ErrStoreArchived
+	}
+	if store.Status != entity.StatusApproved {
+		return gorm.ErrRecordNotFound
+	}
+
+	user, err := u.selfUser(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = u.repo.GetMember(ctx, storeID, user.ID)
+	if err == nil {
+		return entity.ErrUnauthorized
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(
+			"error getting member",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	*review = entity.Review{
+		StoreID: storeID,
+		UserID:  user.ID,
+		Rating:  review.Rating,
+		Text:    u.profanity.Clean(review.Text),
+	}
+	created, err := u.repo.SaveReview(ctx, review)
+	if err != nil {
+		log.Error(
+			"error to save review",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if created {
+		subject := fmt.Sprintf("Your store %s got a %d star review", store.Name, review.Rating)
+		u.notify(ctx, store.UserID, store.User.Email, storePhone(store), entity.NotifyNewReview, subject, subject)
+	}
+
+	return nil
+}
+
+// GetReviews lists a page of the visible reviews of a store.
+func (u *User) GetReviews(ctx context.Context, id string, page entity.PageRequest) (*entity.Page[entity.Review], error) {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result, next, err := u.repo.GetReviews(ctx, storeID, page)
+	if err != nil {
+		log.Error(
+			"error getting reviews",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return &entity.Page[entity.Review]{
+		Items:      result,
+		NextCursor: next,
+	}, nil
+}
+
+// ReplyReview sets the store reply to a review, allowed to its owner and managers.
+func (u *User) ReplyReview(ctx context.Context, id string, reviewID string, reply *entity.ReviewReply) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	rID, err := strconv.Atoi(reviewID)
+	if err != nil {
+		log.Error(
+			"error validating review id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if len([]rune(reply.Reply)) > maxReviewLength {
+		return entity.ErrInvalidReview
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, false, entity.RoleOwner, entity.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	review, err := u.repo.GetReview(ctx, rID)
+	if err != nil {
+		log.Error(
+			"error getting review",
+			zap.Error(err),
+		)
+		return err
+	}
+	if review.StoreID != storeID {
+		return gorm.ErrRecordNotFound
+	}
+
+	reply.Reply = u.profanity.Clean(reply.Reply)
+	err = u.repo.ReplyReview(ctx, rID, reply.Reply)
+	if err != nil {
+		log.Error(
+			"error to reply review",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if reply.Reply != "" {
+		u.notifyReviewer(ctx, storeID, review, reply.Reply)
+	}
+
+	return nil
+}
+
+// notifyReviewer tells the author of a review that the store replied to it.
+func (u *User) notifyReviewer(ctx context.Context, storeID int, review *entity.Review, reply string) {
+	log := zap.NewNop()
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Warn(
+			"error to get store",
+			zap.Error(err),
+		)
+		return
+	}
+	user, err := u.repo.GetUserByID(ctx, review.UserID)
+	if err != nil {
+		log.Warn(
+			"error getting reviewer",
+			zap.Error(err),
+		)
+		return
+	}
+
+	phone := ""
+	profile, err := u.repo.GetUserProfile(ctx, user.Email)
+	if err == nil && profile.PhoneVerified {
+		phone = profile.Phone
+	}
+
+	subject := fmt.Sprintf("%s replied to your review", store.Name)
+	u.notify(ctx, user.ID, user.Email, phone, entity.NotifyReviewReply, subject, fmt.Sprintf("%s: %s", subject, reply))
+}
+
+// HideReview hides or shows a review, allowed to admins.
+func (u *User) HideReview(ctx context.Context, reviewID string, hidden bool) error {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	rID, err := strconv.Atoi(reviewID)
+	if err != nil {
+		log.Error(
+			"error validating review id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	review, err := u.repo.GetReview(ctx, rID)
+	if err != nil {
+		log.Error(
+			"error getting review",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.repo.HideReview(ctx, review, hidden)
+	if err != nil {
+		log.Error(
+			"error to hide review",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
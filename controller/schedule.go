@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultTimezone = "America/Sao_Paulo"
+	clockLayout     = "15:04"
+	dateLayout      = "2006-01-02"
+	// maxOpenNowFetches bounds the reads filling a page of open stores.
+	maxOpenNowFetches = 5
+)
+
+// UpdateSchedule replaces the hours, holidays and vacation mode of a store
+// still at the given version.
+func (u *User) UpdateSchedule(ctx context.Context, id string, version int, schedule *entity.StoreSchedule) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, true, entity.RoleOwner, entity.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = validateSchedule(schedule)
+	if err != nil {
+		return err
+	}
+
+	err = u.repo.UpdateSchedule(ctx, storeID, version, schedule)
+	if err != nil {
+		log.Error(
+			"error to update schedule",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// validateSchedule checks the schedule timezone, weekdays, times and dates,
+// an empty timezone becomes the default one.
+func validateSchedule(schedule *entity.StoreSchedule) error {
+	if schedule.Timezone == "" {
+		schedule.Timezone = defaultTimezone
+	}
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", entity.ErrInvalidSchedule, schedule.Timezone)
+	}
+
+	for _, h := range schedule.Hours {
+		if h.Weekday < 0 || h.Weekday > 6 {
+			return fmt.Errorf("%w: weekday %d out of range", entity.ErrInvalidSchedule, h.Weekday)
+		}
+		if err := validateInterval(h.Opens, h.Closes); err != nil {
+			return err
+		}
+	}
+
+	for _, h := range schedule.Holidays {
+		if _, err := time.Parse(dateLayout, h.Date); err != nil {
+			return fmt.Errorf("%w: date %q isn't YYYY-MM-DD", entity.ErrInvalidSchedule, h.Date)
+		}
+		if h.Closed {
+			continue
+		}
+		if err := validateInterval(h.Opens, h.Closes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateInterval(opens string, closes string) error {
+	for _, clock := range []string{opens, closes} {
+		if _, err := time.Parse(clockLayout, clock); err != nil || len(clock) != len(clockLayout) {
+			return fmt.Errorf("%w: time %q isn't HH:MM", entity.ErrInvalidSchedule, clock)
+		}
+	}
+	if opens == closes {
+		return fmt.Errorf("%w: empty interval at %s", entity.ErrInvalidSchedule, opens)
+	}
+	return nil
+}
+
+// openNow tells whether the store is open at the given instant in its timezone,
+// holidays override the weekly hours and vacations last until the reopen date.
+func openNow(store *entity.Store, now time.Time) bool {
+	if store.Vacation && (store.ReopenAt == nil || now.Before(*store.ReopenAt)) {
+		return false
+	}
+
+	timezone := store.Timezone
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return false
+	}
+	local := now.In(loc)
+	clock := local.Format(clockLayout)
+
+	today := local.Format(dateLayout)
+	yesterday := local.AddDate(0, 0, -1).Format(dateLayout)
+	todayHoliday, yesterdayHoliday := false, false
+	for _, h := range store.Holidays {
+		switch h.Date {
+		case today:
+			todayHoliday = true
+			if !h.Closed && inInterval(clock, h.Opens, h.Closes, false) {
+				return true
+			}
+		case yesterday:
+			yesterdayHoliday = true
+			if !h.Closed && inInterval(clock, h.Opens, h.Closes, true) {
+				return true
+			}
+		}
+	}
+
+	weekday := int(local.Weekday())
+	previous := (weekday + 6) % 7
+	for _, h := range store.Hours {
+		if h.Weekday == weekday && !todayHoliday && inInterval(clock, h.Opens, h.Closes, false) {
+			return true
+		}
+		if h.Weekday == previous && !yesterdayHoliday && inInterval(clock, h.Opens, h.Closes, true) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inInterval tells whether the clock is inside an interval, intervals closing
+// past midnight spill over to the next day.
+func inInterval(clock string, opens string, closes string, spill bool) bool {
+	overnight := closes < opens
+	if spill {
+		return overnight && clock < closes
+	}
+	if overnight {
+		return clock >= opens
+	}
+	return clock >= opens && clock < closes
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"errors"
+	"github.com/restore/user/entity"
+	"testing"
+	"time"
+)
+
+func TestOpenNow(t *testing.T) {
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Monday, June 3rd 2024.
+	at := func(day int, clock string) time.Time {
+		c, err := time.Parse(clockLayout, clock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return time.Date(2024, time.June, day, c.Hour(), c.Minute(), 0, 0, loc)
+	}
+	reopen := at(10, "00:00")
+
+	weekdays := []entity.StoreHours{
+		{Weekday: 1, Opens: "09:00", Closes: "18:00"},
+		{Weekday: 2, Opens: "09:00", Closes: "18:00"},
+		{Weekday: 5, Opens: "20:00", Closes: "02:00"},
+	}
+	tests := []struct {
+		name  string
+		store entity.Store
+		now   time.Time
+		want  bool
+	}{
+		{"inside the hours", entity.Store{Hours: weekdays}, at(3, "10:00"), true},
+		{"at opening", entity.Store{Hours: weekdays}, at(3, "09:00"), true},
+		{"at closing", entity.Store{Hours: weekdays}, at(3, "18:00"), false},
+		{"before opening", entity.Store{Hours: weekdays}, at(3, "08:59"), false},
+		{"closed weekday", entity.Store{Hours: weekdays}, at(5, "10:00"), false},
+		{"overnight before midnight", entity.Store{Hours: weekdays}, at(7, "23:00"), true},
+		{"overnight after midnight", entity.Store{Hours: weekdays}, at(8, "01:30"), true},
+		{"overnight after closing", entity.Store{Hours: weekdays}, at(8, "02:00"), false},
+		{"no hours", entity.Store{}, at(3, "10:00"), false},
+		{
+			"other timezone",
+			entity.Store{Timezone: "America/Manaus", Hours: weekdays},
+			at(3, "09:30"),
+			false,
+		},
+		{
+			"closed holiday",
+			entity.Store{Hours: weekdays, Holidays: []entity.StoreHoliday{{Date: "2024-06-03", Closed: true}}},
+			at(3, "10:00"),
+			false,
+		},
+		{
+			"holiday hours replace the weekday",
+			entity.Store{Hours: weekdays, Holidays: []entity.StoreHoliday{{Date: "2024-06-03", Opens: "12:00", Closes: "14:00"}}},
+			at(3, "10:00"),
+			false,
+		},
+		{
+			"inside holiday hours",
+			entity.Store{Hours: weekdays, Holidays: []entity.StoreHoliday{{Date: "2024-06-03", Opens: "12:00", Closes: "14:00"}}},
+			at(3, "13:00"),
+			true,
+		},
+		{
+			"holiday on a closed weekday",
+			entity.Store{Hours: weekdays, Holidays: []entity.StoreHoliday{{Date: "2024-06-09", Opens: "10:00", Closes: "16:00"}}},
+			at(9, "11:00"),
+			true,
+		},
+		{
+			"closed holiday stops the overnight spill",
+			entity.Store{Hours: weekdays, Holidays: []entity.StoreHoliday{{Date: "2024-06-07", Closed: true}}},
+			at(8, "01:00"),
+			false,
+		},
+		{
+			"overnight holiday spills over",
+			entity.Store{Holidays: []entity.StoreHoliday{{Date: "2024-06-04", Opens: "22:00", Closes: "03:00"}}},
+			at(5, "02:00"),
+			true,
+		},
+		{"vacation", entity.Store{Hours: weekdays, Vacation: true}, at(3, "10:00"), false},
+		{"vacation until reopening", entity.Store{Hours: weekdays, Vacation: true, ReopenAt: &reopen}, at(3, "10:00"), false},
+		{"vacation after reopening", entity.Store{Hours: weekdays, Vacation: true, ReopenAt: &reopen}, at(11, "10:00"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := openNow(&tt.store, tt.now); got != tt.want {
+				t.Errorf("openNow at %s = %v, want %v", tt.now.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule entity.StoreSchedule
+		wantErr  error
+	}{
+		{"empty", entity.StoreSchedule{}, nil},
+		{"hours", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 0, Opens: "09:00", Closes: "18:00"}}}, nil},
+		{"overnight hours", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 6, Opens: "22:00", Closes: "02:00"}}}, nil},
+		{"closed holiday", entity.StoreSchedule{Holidays: []entity.StoreHoliday{{Date: "2024-12-25", Closed: true}}}, nil},
+		{"holiday hours", entity.StoreSchedule{Holidays: []entity.StoreHoliday{{Date: "2024-12-24", Opens: "08:00", Closes: "12:00"}}}, nil},
+		{"unknown timezone", entity.StoreSchedule{Timezone: "Mars/Olympus"}, entity.ErrInvalidSchedule},
+		{"weekday out of range", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 7, Opens: "09:00", Closes: "18:00"}}}, entity.ErrInvalidSchedule},
+		{"unpadded hour", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 1, Opens: "9:00", Closes: "18:00"}}}, entity.ErrInvalidSchedule},
+		{"invalid time", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 1, Opens: "09:00", Closes: "24:00"}}}, entity.ErrInvalidSchedule},
+		{"missing time", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 1, Opens: "09:00"}}}, entity.ErrInvalidSchedule},
+		{"empty interval", entity.StoreSchedule{Hours: []entity.StoreHours{{Weekday: 1, Opens: "09:00", Closes: "09:00"}}}, entity.ErrInvalidSchedule},
+		{"invalid date", entity.StoreSchedule{Holidays: []entity.StoreHoliday{{Date: "25/12/2024", Closed: true}}}, entity.ErrInvalidSchedule},
+		{"holiday without hours", entity.StoreSchedule{Holidays: []entity.StoreHoliday{{Date: "2024-12-24"}}}, entity.ErrInvalidSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchedule(&tt.schedule)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateSchedule error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && tt.schedule.Timezone == "" {
+				t.Error("timezone wasn't defaulted")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"go.uber.org/zap"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// minTermLength is the MySQL innodb_ft_min_token_size, shorter words
+	// aren't indexed and fall back to the name search.
+	minTermLength = 3
+	maxTerms      = 10
+)
+
+// RefreshSearchText folds the search text of the stores that aren't folded yet.
+func (u *User) RefreshSearchText(ctx context.Context) error {
+	log := zap.NewNop()
+
+	updated, err := u.repo.RefreshSearchText(ctx)
+	if err != nil {
+		log.Error(
+			"error refreshing search text",
+			zap.Error(err),
+		)
+		return err
+	}
+	log.Info(
+		"search text refreshed",
+		zap.Int("stores", updated),
+	)
+
+	return nil
+}
+
+// searchTerms splits a search into the folded words the full-text index can match.
+func searchTerms(search string) []string {
+	var terms []string
+	words := strings.FieldsFunc(normalize.Fold(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < minTermLength {
+			continue
+		}
+		terms = append(terms, word)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// highlightStore marks the words starting with a search term in the store
+// text fields, only fields with matches are kept.
+func highlightStore(store *entity.Store, terms []string) {
+	fields := map[string]string{
+		"name":        store.Name,
+		"city":        store.City,
+		"description": store.Description,
+		"tags":        strings.Join(store.Tags, ", "),
+	}
+
+	store.Highlights = map[string]string{}
+	for field, text := range fields {
+		if marked, ok := highlight(text, terms); ok {
+			store.Highlights[field] = marked
+		}
+	}
+}
+
+// highlight escapes the text as HTML and wraps the matching words in <mark>.
+func highlight(text string, terms []string) (string, bool) {
+	var b strings.Builder
+	found := false
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			j := i
+			for j < len(runes) && !isWordRune(runes[j]) {
+				j++
+			}
+			b.WriteString(html.EscapeString(string(runes[i:j])))
+			i = j
+			continue
+		}
+
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if matchesTerm(normalize.Fold(word), terms) {
+			found = true
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		i = j
+	}
+
+	return b.String(), found
+}
+
+func matchesTerm(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/entity"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{"São Paulo", []string{"sao", "paulo"}},
+		{"Brechó", []string{"brecho"}},
+		{"AÇAÍ e PÃO", []string{"acai", "pao"}},
+		{"limões, maçãs!", []string{"limoes", "macas"}},
+		{"loja-da-ana", []string{"loja", "ana"}},
+		{"de um a", nil},
+		{"", nil},
+		{
+			"um dois tres quatro cinco seis sete oito nove dez onze doze",
+			[]string{"dois", "tres", "quatro", "cinco", "seis", "sete", "oito", "nove", "dez", "onze"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			if got := searchTerms(tt.search); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tt.search, got, tt.want)
+			}
+		})
+	}
+}
+
+// pagedRepo serves the stores in pages whose cursor is the offset of the next one.
+type pagedRepo struct {
+	repository
+	stores  []entity.Store
+	fetches int
+}
+
+func (r *pagedRepo) SearchStore(ctx context.Context, filter *entity.StoreFilter) ([]entity.Store, string, error) {
+	r.fetches++
+	offset, _ := strconv.Atoi(filter.Page.Cursor)
+	end := offset + filter.Page.Limit
+	if filter.Page.Limit <= 0 {
+		end = offset + 20
+	}
+	if end >= len(r.stores) {
+		return append([]entity.Store(nil), r.stores[offset:]...), "", nil
+	}
+	return append([]entity.Store(nil), r.stores[offset:end]...), strconv.Itoa(end), nil
+}
+
+func (r *pagedRepo) StoreFacets(ctx context.Context, filter *entity.StoreFilter) (*entity.StoreFacets, error) {
+	return &entity.StoreFacets{}, nil
+}
+
+// openStores builds a store per letter, `o` ones always open and the others on vacation.
+func openStores(pattern string) []entity.Store {
+	var always []entity.StoreHours
+	for weekday := 0; weekday < 7; weekday++ {
+		always = append(always,
+			entity.StoreHours{Weekday: weekday, Opens: "00:00", Closes: "12:00"},
+			entity.StoreHours{Weekday: weekday, Opens: "12:00", Closes: "00:00"},
+		)
+	}
+
+	stores := make([]entity.Store, len(pattern))
+	for i, c := range pattern {
+		stores[i] = entity.Store{ID: i + 1, Hours: always, Vacation: c != 'o'}
+	}
+	return stores
+}
+
+func TestSearchStoreFillsOpenNowPages(t *testing.T) {
+	tests := []struct {
+		name        string
+		stores      string
+		openNow     bool
+		limit       int
+		wantIDs     []int
+		wantNext    bool
+		wantFetches int
+	}{
+		{"fills the page", "oxxoxoxxxo", true, 3, []int{1, 4, 6}, true, 3},
+		{"first page is full", "oooxo", true, 3, []int{1, 2, 3}, true, 1},
+		{"last page comes short", "oxxxx", true, 3, []int{1}, false, 2},
+		{"no open stores", "xxxxxxxx", true, 2, nil, false, 4},
+		{"stops after the fetch limit", strings.Repeat("x", 20), true, 2, nil, true, maxOpenNowFetches},
+		{"without open_now", "oxo", false, 2, []int{1, 2}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &pagedRepo{stores: openStores(tt.stores)}
+			u := &User{repo: repo}
+
+			search, err := u.SearchStore(context.Background(), &entity.StoreFilter{
+				OpenNow: tt.openNow,
+				Page:    entity.PageRequest{Limit: tt.limit},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []int
+			for _, store := range search.Items {
+				ids = append(ids, store.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("store IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			if next := search.NextCursor != ""; next != tt.wantNext {
+				t.Errorf("next cursor %q, want one: %v", search.NextCursor, tt.wantNext)
+			}
+			if repo.fetches != tt.wantFetches {
+				t.Errorf("%d fetches, want %d", repo.fetches, tt.wantFetches)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// maxSlugAttempts bounds the numeric suffixes tried before giving up on a slug.
+const maxSlugAttempts = 100
+
+// GetStoreBySlug gets a store by its slug, moved tells the slug is an old one
+// and the store must be reached by its current slug.
+func (u *User) GetStoreBySlug(ctx context.Context, slug string) (store *entity.Store, moved bool, err error) {
+	log := zap.NewNop()
+
+	slug = normalize.Slug(slug)
+	current, err := u.repo.GetStoreBySlug(ctx, slug)
+	if err == nil {
+		store, err = u.GetStore(ctx, strconv.Itoa(current.ID))
+		return store, false, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(
+			"error to get store",
+			zap.Error(err),
+		)
+		return nil, false, err
+	}
+
+	redirect, err := u.repo.GetSlugRedirect(ctx, slug)
+	if err != nil {
+		log.Error(
+			"error to get store slug",
+			zap.Error(err),
+		)
+		return nil, false, err
+	}
+
+	store, err = u.GetStore(ctx, strconv.Itoa(redirect.StoreID))
+	return store, true, err
+}
+
+// storeSlug picks an unique slug for the store name, keeping the current one
+// while the name still produces it.
+func (u *User) storeSlug(ctx context.Context, storeID int, name string) (string, error) {
+	base := normalize.Slug(name)
+	if base == "" {
+		base = "store"
+	}
+
+	if storeID != 0 {
+		current, err := u.repo.GetStoreByID(ctx, storeID)
+		if err != nil {
+			return "", err
+		}
+		if normalize.Slug(current.Name) == normalize.Slug(name) && current.Slug != "" {
+			return current.Slug, nil
+		}
+	}
+
+	return u.uniqueSlug(ctx, storeID, base)
+}
+
+// uniqueSlug adds a numeric suffix to the base slug only when it's taken.
+func (u *User) uniqueSlug(ctx context.Context, storeID int, base string) (string, error) {
+	slug := base
+	for i := 2; i <= maxSlugAttempts+1; i++ {
+		taken, err := u.repo.SlugTaken(ctx, slug, storeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	return "", fmt.Errorf("no slug available for %q", base)
+}
+
+// RefreshSlugs gives a slug to the stores created before slugs existed.
+func (u *User) RefreshSlugs(ctx context.Context) error {
+	log := zap.NewNop()
+
+	stores, err := u.repo.GetStoresWithoutSlug(ctx)
+	if err != nil {
+		log.Error(
+			"error to get stores without slug",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	for _, store := range stores {
+		base := normalize.Slug(store.Name)
+		if base == "" {
+			base = "store"
+		}
+		slug, err := u.uniqueSlug(ctx, store.ID, base)
+		if err != nil {
+			log.Error(
+				"error picking store slug",
+				zap.Error(err),
+			)
+			return err
+		}
+
+		err = u.repo.UpdateStoreSlug(ctx, store.ID, slug)
+		if err != nil {
+			log.Error(
+				"error to update store slug",
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+	log.Info(
+		"store slugs refreshed",
+		zap.Int("stores", len(stores)),
+	)
+
+	return nil
+}
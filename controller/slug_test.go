@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/restore/user/entity"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// slugRepo holds the current slugs of the stores and the old ones redirecting to them.
+type slugRepo struct {
+	repository
+	stores    map[int]*entity.Store
+	redirects map[string]int
+}
+
+func (r *slugRepo) GetStoreByID(ctx context.Context, id int) (*entity.Store, error) {
+	store, ok := r.stores[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	result := *store
+	return &result, nil
+}
+
+func (r *slugRepo) GetStoreBySlug(ctx context.Context, slug string) (*entity.Store, error) {
+	for _, store := range r.stores {
+		if store.Slug == slug {
+			return r.GetStoreByID(ctx, store.ID)
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *slugRepo) GetSlugRedirect(ctx context.Context, slug string) (*entity.StoreSlug, error) {
+	storeID, ok := r.redirects[slug]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &entity.StoreSlug{Slug: slug, StoreID: storeID}, nil
+}
+
+func (r *slugRepo) SlugTaken(ctx context.Context, slug string, storeID int) (bool, error) {
+	for _, store := range r.stores {
+		if store.Slug == slug && store.ID != storeID {
+			return true, nil
+		}
+	}
+	owner, ok := r.redirects[slug]
+	return ok && owner != storeID, nil
+}
+
+func (r *slugRepo) CountFollowers(ctx context.Context, storeID int) (int64, error) {
+	return 0, nil
+}
+
+func newSlugRepo(slugs ...string) *slugRepo {
+	repo := &slugRepo{stores: map[int]*entity.Store{}, redirects: map[string]int{}}
+	for i, slug := range slugs {
+		repo.stores[i+1] = &entity.Store{ID: i + 1, Name: slug, Slug: slug, Status: entity.StatusApproved}
+	}
+	return repo
+}
+
+func TestStoreSlug(t *testing.T) {
+	taken := func(n int) []string {
+		slugs := []string{"brecho"}
+		for i := 2; i <= n; i++ {
+			slugs = append(slugs, fmt.Sprintf("brecho-%d", i))
+		}
+		return slugs
+	}
+	tests := []struct {
+		name      string
+		slugs     []string
+		redirects map[string]int
+		storeID   int
+		current   string
+		storeName string
+		want      string
+		wantErr   bool
+	}{
+		{"free slug", nil, nil, 0, "", "Brechó", "brecho", false},
+		{"taken slug", taken(1), nil, 0, "", "Brechó", "brecho-2", false},
+		{"taken suffixes", taken(3), nil, 0, "", "Brechó", "brecho-4", false},
+		{"slug redirecting to another store", nil, map[string]int{"brecho": 9}, 0, "", "Brechó", "brecho-2", false},
+		{"old slug of the same store", []string{"moda"}, map[string]int{"brecho": 1}, 1, "Moda", "Brechó", "brecho", false},
+		{"name without letters", nil, nil, 0, "", "!!!", "store", false},
+		{"same name keeps a suffixed slug", []string{"brecho-2"}, nil, 1, "Brechó", "BRECHÓ", "brecho-2", false},
+		{"renamed store", []string{"moda"}, nil, 1, "Moda", "Brechó", "brecho", false},
+		{"every suffix taken", taken(maxSlugAttempts + 1), nil, 0, "", "Brechó", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newSlugRepo(tt.slugs...)
+			for slug, storeID := range tt.redirects {
+				repo.redirects[slug] = storeID
+			}
+			if tt.current != "" {
+				repo.stores[tt.storeID].Name = tt.current
+			}
+			u := &User{repo: repo}
+
+			got, err := u.storeSlug(context.Background(), tt.storeID, tt.storeName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("storeSlug(%q) error = %v, want an error: %v", tt.storeName, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("storeSlug(%q) = %q, want %q", tt.storeName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStoreBySlug(t *testing.T) {
+	tests := []struct {
+		name      string
+		slug      string
+		wantID    int
+		wantMoved bool
+		wantErr   error
+	}{
+		{"current slug", "brecho-da-ana", 1, false, nil},
+		{"unnormalized slug", "Brechó da Ana", 1, false, nil},
+		{"old slug", "bazar-da-ana", 1, true, nil},
+		{"unknown slug", "loja", 0, false, gorm.ErrRecordNotFound},
+		{"old slug of an archived store", "moda-antiga", 0, true, entity.ErrStoreArchived},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newSlugRepo("brecho-da-ana", "moda")
+			repo.stores[2].ArchivedAt = gorm.DeletedAt{Valid: true}
+			repo.redirects["bazar-da-ana"] = 1
+			repo.redirects["moda-antiga"] = 2
+			u := &User{repo: repo}
+
+			store, moved, err := u.GetStoreBySlug(context.Background(), tt.slug)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetStoreBySlug(%q) error = %v, want %v", tt.slug, err, tt.wantErr)
+			}
+			if moved != tt.wantMoved {
+				t.Errorf("GetStoreBySlug(%q) moved = %v, want %v", tt.slug, moved, tt.wantMoved)
+			}
+			if err == nil && store.ID != tt.wantID {
+				t.Errorf("GetStoreBySlug(%q) = store %d, want %d", tt.slug, store.ID, tt.wantID)
+			}
+		})
+	}
+}
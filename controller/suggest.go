@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSuggestions = 10
+	maxSuggestions     = 20
+)
+
+// LoadSuggestions builds the store name index from the public stores.
+func (u *User) LoadSuggestions(ctx context.Context) error {
+	log := zap.NewNop()
+
+	stores, err := u.repo.GetStoreSuggestions(ctx)
+	if err != nil {
+		log.Error(
+			"error getting store suggestions",
+			zap.Error(err),
+		)
+		return err
+	}
+	u.suggester.Load(stores)
+
+	return nil
+}
+
+// SuggestStores lists the public stores whose name starts with the prefix,
+// or has a word starting with it.
+func (u *User) SuggestStores(ctx context.Context, prefix string, limit int) []entity.StoreSuggestion {
+	if limit <= 0 {
+		limit = defaultSuggestions
+	}
+	if limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+	return u.suggester.Suggest(prefix, limit)
+}
+
+// reindexStore refreshes a store in the name index, it's only kept there
+// while approved and not archived.
+func (u *User) reindexStore(ctx context.Context, storeID int) {
+	log := zap.NewNop()
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Warn(
+			"error reindexing store",
+			zap.Error(err),
+		)
+		u.suggester.Remove(storeID)
+		return
+	}
+	if store.Status != entity.StatusApproved || store.ArchivedAt.Valid {
+		u.suggester.Remove(storeID)
+		return
+	}
+
+	u.suggester.Put(entity.StoreSuggestion{
+		ID:   store.ID,
+		Name: store.Name,
+		Slug: store.Slug,
+	})
+}
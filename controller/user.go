@@ -5,9 +5,14 @@ import (
 	"errors"
 	"github.com/restore/user/config"
 	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+	"github.com/restore/user/service"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"io"
 	"strconv"
+	"time"
 )
 
 type repository interface {
@@ -15,33 +20,174 @@ type repository interface {
 	CreateProfile(ctx context.Context, profile *entity.Profile) error
 	CreateStore(ctx context.Context, store *entity.Store) error
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetUserByID(ctx context.Context, id int) (*entity.User, error)
+	GetUserCredentials(ctx context.Context, email string) (*entity.User, error)
 	GetProfileByID(ctx context.Context, id int) (*entity.Profile, error)
 	GetStoreByID(ctx context.Context, id int) (*entity.Store, error)
-	SearchStore(ctx context.Context, name string) ([]entity.Store, error)
-	UpdateProfile(ctx context.Context, id int, profile *entity.Profile) error
-	GetUserStore(ctx context.Context, email string) (*entity.Store, error)
+	SearchStore(ctx context.Context, filter *entity.StoreFilter) ([]entity.Store, string, error)
+	StoreFacets(ctx context.Context, filter *entity.StoreFilter) (*entity.StoreFacets, error)
+	UpdateProfile(ctx context.Context, id int, version int, profile *entity.Profile) error
+	UpdateStore(ctx context.Context, id int, version int, store *entity.Store) error
+	GetUserStores(ctx context.Context, email string) ([]entity.StoreMember, error)
 	GetUserProfile(ctx context.Context, email string) (*entity.Profile, error)
+	ArchiveStore(ctx context.Context, id int) error
+	RestoreStore(ctx context.Context, id int, since time.Time) error
+	GetArchivedStores(ctx context.Context, before time.Time) ([]entity.Store, error)
+	PurgeStore(ctx context.Context, store *entity.Store, cleanup func(userDeleted bool) error) (bool, error)
+	CreateMember(ctx context.Context, member *entity.StoreMember) error
+	GetMember(ctx context.Context, storeID int, userID int) (*entity.StoreMember, error)
+	GetMembers(ctx context.Context, storeID int) ([]entity.StoreMember, error)
+	DeleteMember(ctx context.Context, storeID int, userID int) error
+	GetAddresses(ctx context.Context, userID int) ([]entity.Address, error)
+	CreateAddress(ctx context.Context, address *entity.Address) error
+	UpdateAddress(ctx context.Context, address *entity.Address) error
+	DeleteAddress(ctx context.Context, userID int, id int) error
+	CreatePhoneVerification(ctx context.Context, verification *entity.PhoneVerification) error
+	GetPhoneVerification(ctx context.Context, userID int, phone string) (*entity.PhoneVerification, error)
+	IncrementPhoneAttempts(ctx context.Context, id int) error
+	ConfirmPhone(ctx context.Context, userID int, phone string) error
+	UpdateSchedule(ctx context.Context, id int, version int, schedule *entity.StoreSchedule) error
+	NearbyStores(ctx context.Context, minLat, maxLat, minLng, maxLng float64) ([]entity.Store, error)
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+	GetCategoriesByIDs(ctx context.Context, ids []int) ([]entity.Category, error)
+	CreateCategory(ctx context.Context, category *entity.Category) error
+	UpdateCategory(ctx context.Context, category *entity.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	GetDefaultAddress(ctx context.Context, email string) (*entity.Address, error)
+	SaveReview(ctx context.Context, review *entity.Review) (bool, error)
+	GetReview(ctx context.Context, id int) (*entity.Review, error)
+	GetReviews(ctx context.Context, storeID int, page entity.PageRequest) ([]entity.Review, string, error)
+	ReplyReview(ctx context.Context, id int, reply string) error
+	HideReview(ctx context.Context, review *entity.Review, hidden bool) error
+	AddFavorite(ctx context.Context, favorite *entity.Favorite) (bool, error)
+	DeleteFavorite(ctx context.Context, userID int, storeID int) error
+	GetFavorites(ctx context.Context, userID int, page entity.PageRequest) ([]entity.Favorite, string, error)
+	CountFollowers(ctx context.Context, storeID int) (int64, error)
+	GetFollowers(ctx context.Context, storeID int) ([]entity.User, error)
+	GetStoresByStatus(ctx context.Context, status string, page entity.PageRequest) ([]entity.Store, string, error)
+	UpdateStoreStatus(ctx context.Context, store *entity.Store) error
+	GetStoreBySlug(ctx context.Context, slug string) (*entity.Store, error)
+	GetSlugRedirect(ctx context.Context, slug string) (*entity.StoreSlug, error)
+	SlugTaken(ctx context.Context, slug string, storeID int) (bool, error)
+	GetStoresWithoutSlug(ctx context.Context) ([]entity.Store, error)
+	UpdateStoreSlug(ctx context.Context, id int, slug string) error
+	GetStoreSuggestions(ctx context.Context) ([]entity.StoreSuggestion, error)
+	UpdateProfileAvatar(ctx context.Context, id int, avatar string) error
+	GetPreferences(ctx context.Context, userID int) (*entity.Preferences, error)
+	SavePreferences(ctx context.Context, preferences *entity.Preferences) error
+	SearchUsers(ctx context.Context, filter *entity.UserFilter) ([]entity.UserSummary, string, error)
+	RefreshSearchText(ctx context.Context) (int, error)
 }
 
 type kong interface {
 	CreateCustomer(email string) error
 	CreateCredentials(email string) (string, error)
+	DeleteCustomer(email string) error
+}
+
+type storage interface {
+	Delete(name string) error
+}
+
+type postal interface {
+	Lookup(ctx context.Context, cep string) (*entity.PostalAddress, error)
+}
+
+type sms interface {
+	Send(phone string, message string) error
+}
+
+type geocoder interface {
+	Geocode(ctx context.Context, address string) (float64, float64, error)
+}
+
+type profanity interface {
+	Clean(text string) string
+}
+
+type notifier interface {
+	Notify(email string, subject string, message string) error
+}
+
+type avatar interface {
+	Save(r io.Reader) (string, error)
+	Delete(name string) error
+	URL(name string) string
+	URLs(name string) map[string]string
+}
+
+type suggester interface {
+	Load(stores []entity.StoreSuggestion)
+	Put(store entity.StoreSuggestion)
+	Remove(id int)
+	Suggest(prefix string, limit int) []entity.StoreSuggestion
 }
 
 type User struct {
-	repo repository
-	kong kong
+	repo      repository
+	kong      kong
+	storage   storage
+	postal    postal
+	sms       sms
+	geocoder  geocoder
+	profanity profanity
+	notifier  notifier
+	avatar    avatar
+	suggester suggester
+	cfg       *config.StoreConfig
+	smsCfg    *service.SMSConfig
 }
 
-func NewUser(r repository, k kong) *User {
+func NewUser(
+	r repository,
+	k kong,
+	s storage,
+	p postal,
+	m sms,
+	g geocoder,
+	pf profanity,
+	n notifier,
+	av avatar,
+	sg suggester,
+	cfg *config.StoreConfig,
+	smsCfg *service.SMSConfig,
+) *User {
 	return &User{
-		repo: r,
-		kong: k,
+		repo:      r,
+		kong:      k,
+		storage:   s,
+		postal:    p,
+		sms:       m,
+		geocoder:  g,
+		profanity: pf,
+		notifier:  n,
+		avatar:    av,
+		suggester: sg,
+		cfg:       cfg,
+		smsCfg:    smsCfg,
 	}
 }
 
 func (u *User) Register(ctx context.Context, profile *entity.Profile) (string, error) {
 	log := zap.NewNop()
+	err := normalizeProfileDocument(profile)
+	if err != nil {
+		log.Error(
+			"error validating document",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	err = u.normalizeAddress(ctx, &profile.ZipCode, &profile.City, &profile.State)
+	if err != nil {
+		log.Error(
+			"error normalizing address",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
 	pass, err := crypt(profile.User.Password)
 	if err != nil {
 		log.Error(
@@ -62,6 +208,7 @@ func (u *User) Register(ctx context.Context, profile *entity.Profile) (string, e
 	}
 
 	profile.UserID = id
+	profile.Version = 1
 	err = u.repo.CreateProfile(ctx, profile)
 	if err != nil {
 		log.Error(
@@ -108,7 +255,67 @@ func (u *User) RegisterStore(ctx context.Context, store *entity.Store) (string,
 		log.Error(
 			"unauthorized action",
 		)
-		return "", errors.New("unauthorized action")
+		return "", entity.ErrUnauthorized
+	}
+
+	err = normalizeStoreDocuments(store)
+	if err != nil {
+		log.Error(
+			"error validating document",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	schedule := &entity.StoreSchedule{
+		Timezone: store.Timezone,
+		Hours:    store.Hours,
+		Holidays: store.Holidays,
+	}
+	err = validateSchedule(schedule)
+	if err != nil {
+		log.Error(
+			"error validating schedule",
+			zap.Error(err),
+		)
+		return "", err
+	}
+	store.Timezone = schedule.Timezone
+
+	err = u.normalizeAddress(ctx, &store.ZipCode, &store.City, &store.State)
+	if err != nil {
+		log.Error(
+			"error normalizing address",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	err = u.locateStore(ctx, store)
+	if err != nil {
+		log.Error(
+			"error locating store",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	err = u.classifyStore(ctx, store)
+	if err != nil {
+		log.Error(
+			"error classifying store",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	store.Slug, err = u.storeSlug(ctx, 0, store.Name)
+	if err != nil {
+		log.Error(
+			"error generating slug",
+			zap.Error(err),
+		)
+		return "", err
 	}
 
 	pass, err := crypt(store.User.Password)
@@ -131,6 +338,14 @@ func (u *User) RegisterStore(ctx context.Context, store *entity.Store) (string,
 	}
 
 	store.UserID = id
+	store.RatingAvg = 0
+	store.RatingCount = 0
+	store.Version = 1
+	submitted := time.Now()
+	store.Status = entity.StatusPending
+	store.RejectionReason = ""
+	store.SubmittedAt = &submitted
+	store.ReviewedAt = nil
 	err = u.repo.CreateStore(ctx, store)
 	if err != nil {
 		log.Error(
@@ -139,6 +354,7 @@ func (u *User) RegisterStore(ctx context.Context, store *entity.Store) (string,
 		)
 		return "", err
 	}
+	u.reindexStore(ctx, store.ID)
 
 	err = u.kong.CreateCustomer(store.User.Email)
 	if err != nil {
@@ -191,7 +407,7 @@ func (u *User) GetProfile(ctx context.Context, id string) (*entity.Profile, erro
 		log.Error(
 			"unauthorized action",
 		)
-		return nil, errors.New("unauthorized action")
+		return nil, entity.ErrUnauthorized
 	}
 
 	profileID, err := strconv.Atoi(id)
@@ -212,6 +428,8 @@ func (u *User) GetProfile(ctx context.Context, id string) (*entity.Profile, erro
 		return nil, err
 	}
 	profile.User.Password = ""
+	u.fillAvatar(profile)
+	u.maskProfileFor(ctx, profile)
 
 	return profile, nil
 }
@@ -236,15 +454,134 @@ func (u *User) GetStore(ctx context.Context, id string) (*entity.Store, error) {
 		)
 		return nil, err
 	}
+	if store.ArchivedAt.Valid {
+		return nil, entity.ErrStoreArchived
+	}
+	if store.Status != entity.StatusApproved && !u.canSeeStore(ctx, storeID) {
+		return nil, gorm.ErrRecordNotFound
+	}
 	store.User.Password = ""
+	store.OpenNow = openNow(store, time.Now())
+	store.FollowerCount, err = u.repo.CountFollowers(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error counting followers",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if !u.canSeeDocuments(ctx, storeID) {
+		maskStore(store)
+	}
 
 	return store, nil
 }
 
-func (u *User) SearchStore(ctx context.Context, name string) ([]entity.Store, error) {
+// UpdateStore updates a store still at the given version, allowed to its
+// owner, managers and admins.
+func (u *User) UpdateStore(ctx context.Context, id string, version int, store *entity.Store) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, true, entity.RoleOwner, entity.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = normalizeStoreDocuments(store)
+	if err != nil {
+		log.Error(
+			"error validating document",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.normalizeAddress(ctx, &store.ZipCode, &store.City, &store.State)
+	if err != nil {
+		log.Error(
+			"error normalizing address",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.locateStore(ctx, store)
+	if err != nil {
+		log.Error(
+			"error locating store",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.classifyStore(ctx, store)
+	if err != nil {
+		log.Error(
+			"error classifying store",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	store.Slug, err = u.storeSlug(ctx, storeID, store.Name)
+	if err != nil {
+		log.Error(
+			"error generating slug",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.repo.UpdateStore(ctx, storeID, version, store)
+	if err != nil {
+		log.Error(
+			"error to update store",
+			zap.Error(err),
+		)
+		return err
+	}
+	u.reindexStore(ctx, storeID)
+
+	return nil
+}
+
+// SearchStore finds a page of the approved stores matching the filter, or of
+// every store for the admin search, which is allowed to admins only. Searches
+// with indexable words are ranked by relevance and highlighted. The
+// open_now filter keeps reading the following stores to fill the page, up to
+// maxOpenNowFetches reads, past which the page comes short with a next cursor.
+// The first page also counts the matches by state, city and category, which
+// don't take open_now into account. Documents are masked except to admins.
+func (u *User) SearchStore(ctx context.Context, filter *entity.StoreFilter) (*entity.StoreSearch, error) {
 	log := zap.NewNop()
 
-	stores, err := u.repo.SearchStore(ctx, name)
+	showDocuments := u.isAdmin(ctx)
+	if filter.AnyStatus && !showDocuments {
+		return nil, entity.ErrUnauthorized
+	}
+
+	if filter.State != "" {
+		uf, err := normalize.UF(filter.State)
+		if err != nil {
+			return nil, err
+		}
+		filter.State = uf
+	}
+	if filter.City != "" {
+		filter.City = normalize.City(filter.City)
+	}
+
+	filter.Terms = searchTerms(filter.Name)
+	stores, next, err := u.repo.SearchStore(ctx, filter)
 	if err != nil {
 		log.Error(
 			"error to get store",
@@ -253,10 +590,63 @@ func (u *User) SearchStore(ctx context.Context, name string) ([]entity.Store, er
 		return nil, err
 	}
 
-	return stores, nil
+	now := time.Now()
+	limit := len(stores)
+	result := make([]entity.Store, 0, limit)
+	for fetches := 1; ; fetches++ {
+		for i := range stores {
+			store := stores[i]
+			if !showDocuments {
+				maskStore(&store)
+			}
+			store.OpenNow = openNow(&store, now)
+			if filter.OpenNow && !store.OpenNow {
+				continue
+			}
+			if len(filter.Terms) > 0 {
+				highlightStore(&store, filter.Terms)
+			}
+			result = append(result, store)
+		}
+		if !filter.OpenNow || next == "" || len(result) >= limit || fetches == maxOpenNowFetches {
+			break
+		}
+
+		following := *filter
+		following.Page = entity.PageRequest{Limit: limit - len(result), Cursor: next}
+		stores, next, err = u.repo.SearchStore(ctx, &following)
+		if err != nil {
+			log.Error(
+				"error to get store",
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	}
+
+	search := &entity.StoreSearch{
+		Page: entity.Page[entity.Store]{
+			Items:      result,
+			NextCursor: next,
+		},
+	}
+	if filter.Page.Cursor == "" {
+		search.Facets, err = u.repo.StoreFacets(ctx, filter)
+		if err != nil {
+			log.Error(
+				"error to get store facets",
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	}
+
+	return search, nil
 }
 
-func (u *User) UpdateProfile(ctx context.Context, id string, profile *entity.Profile) error {
+// UpdateProfile replaces every field of a profile still at the given version,
+// allowed to its owner and admins.
+func (u *User) UpdateProfile(ctx context.Context, id string, version int, profile *entity.Profile) error {
 	log := zap.NewNop()
 
 	profileID, err := strconv.Atoi(id)
@@ -268,23 +658,21 @@ func (u *User) UpdateProfile(ctx context.Context, id string, profile *entity.Pro
 		return err
 	}
 
-	err = u.repo.UpdateProfile(ctx, profileID, profile)
+	_, err = u.requireProfileOwner(ctx, profileID)
 	if err != nil {
-		log.Error(
-			"error to get profile",
-			zap.Error(err),
-		)
 		return err
 	}
 
-	return nil
+	return u.saveProfile(ctx, profileID, version, profile, nil)
 }
 
-func (u *User) GetSelfStore(ctx context.Context) (*entity.Store, error) {
+// GetSelfStore lists the stores the user belongs to, the active one is selected
+// by its ID and defaults to the first store.
+func (u *User) GetSelfStore(ctx context.Context, active string) (*entity.SelfStores, error) {
 	log := zap.NewNop()
 
 	email := ctx.Value(config.EmailHeader)
-	result, err := u.repo.GetUserStore(ctx, email.(string))
+	stores, err := u.repo.GetUserStores(ctx, email.(string))
 	if err != nil {
 		log.Error(
 			"error getting store",
@@ -292,6 +680,39 @@ func (u *User) GetSelfStore(ctx context.Context) (*entity.Store, error) {
 		)
 		return nil, err
 	}
+	if len(stores) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	for _, s := range stores {
+		if s.Role != entity.RoleOwner {
+			maskStore(s.Store)
+		}
+	}
+
+	result := &entity.SelfStores{
+		Active: stores[0].Store,
+		Stores: stores,
+	}
+	if active != "" {
+		activeID, err := strconv.Atoi(active)
+		if err != nil {
+			log.Error(
+				"error validating id",
+				zap.Error(err),
+			)
+			return nil, err
+		}
+
+		result.Active = nil
+		for _, s := range stores {
+			if s.StoreID == activeID {
+				result.Active = s.Store
+			}
+		}
+		if result.Active == nil {
+			return nil, entity.ErrUnauthorized
+		}
+	}
 
 	return result, nil
 }
@@ -308,16 +729,160 @@ func (u *User) GetSelfProfile(ctx context.Context) (*entity.Profile, error) {
 		)
 		return nil, err
 	}
+	u.fillAvatar(result)
+	u.maskProfileFor(ctx, result)
 
 	return result, nil
 }
 
+// DeleteStore archives a store, it's hidden until an admin restores it or it gets purged.
+func (u *User) DeleteStore(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to get store",
+			zap.Error(err),
+		)
+		return err
+	}
+	if store.ArchivedAt.Valid {
+		return entity.ErrStoreArchived
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, true, entity.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	err = u.repo.ArchiveStore(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to archive store",
+			zap.Error(err),
+		)
+		return err
+	}
+	u.suggester.Remove(storeID)
+
+	return nil
+}
+
+// RestoreStore brings back an archived store while it's inside the retention window.
+func (u *User) RestoreStore(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	err = u.repo.RestoreStore(ctx, storeID, u.retentionStart())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		store, getErr := u.repo.GetStoreByID(ctx, storeID)
+		if getErr == nil && store.ArchivedAt.Valid {
+			return entity.ErrRetentionExpired
+		}
+	}
+	if err != nil {
+		log.Error(
+			"error to restore store",
+			zap.Error(err),
+		)
+		return err
+	}
+	u.reindexStore(ctx, storeID)
+
+	return nil
+}
+
+// PurgeArchivedStores hard-deletes the stores archived before the retention window,
+// along with their orphan users, Kong consumers and photos. A store failing to
+// purge is left for the next run without stopping the others.
+func (u *User) PurgeArchivedStores(ctx context.Context) error {
+	log := zap.NewNop()
+
+	stores, err := u.repo.GetArchivedStores(ctx, u.retentionStart())
+	if err != nil {
+		log.Error(
+			"error getting archived stores",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	var errs []error
+	for i := range stores {
+		store := &stores[i]
+		_, err := u.repo.PurgeStore(ctx, store, func(userDeleted bool) error {
+			return u.cleanupStore(store, userDeleted)
+		})
+		if err != nil {
+			log.Error(
+				"error to purge store",
+				zap.Int("store_id", store.ID),
+				zap.Error(err),
+			)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// cleanupStore removes what a purged store leaves outside the database, both
+// removals ignore what's already gone so a failed purge can be retried.
+func (u *User) cleanupStore(store *entity.Store, userDeleted bool) error {
+	log := zap.NewNop()
+
+	if userDeleted {
+		err := u.kong.DeleteCustomer(store.User.Email)
+		if err != nil {
+			log.Error(
+				"error deleting kong consumer",
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+
+	err := u.storage.Delete(store.PhotoPath)
+	if err != nil {
+		log.Error(
+			"error deleting store photo",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
 func (u *User) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	return u.repo.GetUserByEmail(ctx, email)
 }
 
 func (u *User) validate(ctx context.Context, user *entity.User) (bool, error) {
-	result, err := u.repo.GetUserByEmail(ctx, user.Email)
+	result, err := u.repo.GetUserCredentials(ctx, user.Email)
 	if err != nil {
 		return false, err
 	}
@@ -329,6 +894,32 @@ func (u *User) validate(ctx context.Context, user *entity.User) (bool, error) {
 	return result.IsAdmin, nil
 }
 
+func (u *User) requireAdmin(ctx context.Context) error {
+	log := zap.NewNop()
+
+	admin := ctx.Value(config.EmailHeader)
+	result, err := u.repo.GetUserByEmail(ctx, admin.(string))
+	if err != nil {
+		log.Error(
+			"error getting admin",
+			zap.Error(err),
+		)
+		return err
+	}
+	if !result.IsAdmin {
+		log.Error(
+			"unauthorized action",
+		)
+		return entity.ErrUnauthorized
+	}
+
+	return nil
+}
+
+func (u *User) retentionStart() time.Time {
+	return time.Now().AddDate(0, 0, -u.cfg.RetentionDays)
+}
+
 func crypt(text string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(text), bcrypt.MinCost)
 	if err != nil {
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetReviewQueue lists a page of the stores waiting for verification, allowed to admins.
+func (u *User) GetReviewQueue(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Store], error) {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, next, err := u.repo.GetStoresByStatus(ctx, entity.StatusPending, page)
+	if err != nil {
+		log.Error(
+			"error getting pending stores",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	for i := range result {
+		result[i].User.Password = ""
+	}
+
+	return &entity.Page[entity.Store]{
+		Items:      result,
+		NextCursor: next,
+	}, nil
+}
+
+// ApproveStore publishes a pending store, allowed to admins.
+func (u *User) ApproveStore(ctx context.Context, id string) error {
+	return u.reviewStore(ctx, id, entity.StatusApproved, "")
+}
+
+// RejectStore rejects a pending store with a reason, allowed to admins.
+func (u *User) RejectStore(ctx context.Context, id string, rejection *entity.StoreRejection) error {
+	reason := strings.TrimSpace(rejection.Reason)
+	if reason == "" {
+		return errors.New("a rejection reason is required")
+	}
+	return u.reviewStore(ctx, id, entity.StatusRejected, reason)
+}
+
+// ResubmitStore sends a rejected store back to the review queue, allowed to its owner.
+func (u *User) ResubmitStore(ctx context.Context, id string) error {
+	log := zap.NewNop()
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	_, err = u.requireStoreRole(ctx, storeID, false, entity.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to get store",
+			zap.Error(err),
+		)
+		return err
+	}
+	if store.Status != entity.StatusRejected {
+		return entity.ErrInvalidStatus
+	}
+
+	now := time.Now()
+	store.Status = entity.StatusPending
+	store.SubmittedAt = &now
+	err = u.repo.UpdateStoreStatus(ctx, store)
+	if err != nil {
+		log.Error(
+			"error to update store status",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (u *User) reviewStore(ctx context.Context, id string, status string, reason string) error {
+	log := zap.NewNop()
+
+	err := u.requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	storeID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error(
+			"error validating id",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	store, err := u.repo.GetStoreByID(ctx, storeID)
+	if err != nil {
+		log.Error(
+			"error to get store",
+			zap.Error(err),
+		)
+		return err
+	}
+	if store.Status != entity.StatusPending {
+		return entity.ErrInvalidStatus
+	}
+
+	now := time.Now()
+	store.Status = status
+	store.RejectionReason = reason
+	store.ReviewedAt = &now
+	err = u.repo.UpdateStoreStatus(ctx, store)
+	if err != nil {
+		log.Error(
+			"error to update store status",
+			zap.Error(err),
+		)
+		return err
+	}
+	u.reindexStore(ctx, storeID)
+
+	subject := fmt.Sprintf("Your store %s was %s", store.Name, status)
+	message := subject
+	if reason != "" {
+		message = fmt.Sprintf("%s: %s", subject, reason)
+	}
+	u.notify(ctx, store.UserID, store.User.Email, storePhone(store), entity.NotifyStoreVerification, subject, message)
+
+	return nil
+}
+
+// canSeeStore tells whether the caller may see a store that isn't approved,
+// which is limited to its members and admins.
+func (u *User) canSeeStore(ctx context.Context, storeID int) bool {
+	email, _ := ctx.Value(config.EmailHeader).(string)
+	if email == "" {
+		return false
+	}
+
+	_, err := u.requireStoreRole(ctx, storeID, true, entity.RoleOwner, entity.RoleManager, entity.RoleStaff)
+	return err == nil
+}
@@ -0,0 +1,15 @@
+package entity
+
+// Address represents data about an address of the user address book.
+type Address struct {
+	ID              int    `json:"id" gorm:"primaryKey"`
+	UserID          int    `json:"user_id"`
+	Label           string `json:"label" binding:"max=50"`
+	Address         string `json:"address" binding:"required,max=100"`
+	Block           string `json:"block" binding:"max=100"`
+	ZipCode         string `json:"zip_code" binding:"max=9"`
+	City            string `json:"city" binding:"max=100"`
+	State           string `json:"state" binding:"max=100"`
+	DefaultShipping bool   `json:"default_shipping"`
+	DefaultBilling  bool   `json:"default_billing"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// User roles an admin can search for, besides the store roles.
+const (
+	RoleAdmin    = "admin"
+	RoleCustomer = "customer"
+)
+
+// StatusArchived matches users with archived stores on the admin search.
+const StatusArchived = "archived"
+
+// UserFilter represents data about an admin search of users, City and State
+// match either the profile or a store of the user. CreatedFrom and CreatedTo
+// leave out the older users whose creation date is unknown.
+type UserFilter struct {
+	EmailPrefix string
+	Role        string
+	Status      string
+	City        string
+	State       string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Page        PageRequest
+}
+
+// UserSummary represents an user along with its profile and stores.
+type UserSummary struct {
+	User    User          `json:"user"`
+	Profile *Profile      `json:"profile,omitempty"`
+	Stores  []StoreMember `json:"stores,omitempty"`
+}
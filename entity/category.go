@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Category represents data about a store category.
+type Category struct {
+	ID   int    `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// Tags represents the free-form tags of a store, stored comma separated.
+type Tags []string
+
+func (t Tags) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+func (t *Tags) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("unsupported tags type %T", value)
+	}
+
+	*t = Tags{}
+	if text != "" {
+		*t = strings.Split(text, ",")
+	}
+	return nil
+}
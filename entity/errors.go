@@ -0,0 +1,51 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrUnauthorized is returned when the caller can't perform an action.
+	ErrUnauthorized = errors.New("unauthorized action")
+	// ErrStoreArchived is returned when a store was archived by its owner or an admin.
+	ErrStoreArchived = errors.New("store archived")
+	// ErrRetentionExpired is returned when an archived store can't be restored anymore.
+	ErrRetentionExpired = errors.New("retention window expired")
+	// ErrInvalidRole is returned when a store role isn't assignable.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrOwnerRemoval is returned when removing the owner of a store.
+	ErrOwnerRemoval = errors.New("store owner can't be removed")
+	// ErrCEPNotFound is returned when a CEP isn't known by the postal lookup.
+	ErrCEPNotFound = errors.New("CEP not found")
+	// ErrDocumentRequired is returned when a store has neither a CNPJ nor its owner CPF.
+	ErrDocumentRequired = errors.New("CNPJ or CPF is required")
+	// ErrInvalidCode is returned when a verification code is wrong, expired or
+	// had too many attempts.
+	ErrInvalidCode = errors.New("invalid verification code")
+	// ErrTooManyCodes is returned when a phone asks for codes faster than the resend limits.
+	ErrTooManyCodes = errors.New("too many verification codes, try again later")
+	// ErrSMSUnavailable is returned when no SMS sender is configured.
+	ErrSMSUnavailable = errors.New("sms is unavailable")
+	// ErrMailUnavailable is returned when no mailer is configured.
+	ErrMailUnavailable = errors.New("mail is unavailable")
+	// ErrInvalidSchedule is returned when store hours, holidays or timezone are malformed.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrInvalidLocation is returned when coordinates or a search radius are out of range.
+	ErrInvalidLocation = errors.New("invalid location")
+	// ErrInvalidTag is returned when a tag is empty, too long or over the tag limit.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrUnknownCategory is returned when a store refers to a category that doesn't exist.
+	ErrUnknownCategory = errors.New("unknown category")
+	// ErrInvalidCategory is returned when a category name is empty or too long.
+	ErrInvalidCategory = errors.New("invalid category name")
+	// ErrInvalidReview is returned when a review rating is out of 1 to 5 or its text is too long.
+	ErrInvalidReview = errors.New("invalid review")
+	// ErrInvalidStatus is returned when a store verification step doesn't apply to its status.
+	ErrInvalidStatus = errors.New("invalid store status")
+	// ErrInvalidPreferences is returned when a preference has an unknown key or value.
+	ErrInvalidPreferences = errors.New("invalid preferences")
+	// ErrInvalidProfile is returned when a profile misses a required field or changes a protected one.
+	ErrInvalidProfile = errors.New("invalid profile")
+	// ErrVersionMismatch is returned when a record changed since the version the client read.
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrInvalidCursor is returned when a page cursor is malformed or belongs to another sort.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
@@ -0,0 +1,29 @@
+package entity
+
+// Store search facets.
+const (
+	FacetState    = "state"
+	FacetCity     = "city"
+	FacetCategory = "category"
+)
+
+// Facet represents how many stores of a search share a value, Label names
+// values that are IDs.
+type Facet struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// StoreFacets represents the facet counts of a store search.
+type StoreFacets struct {
+	State    []Facet `json:"state"`
+	City     []Facet `json:"city"`
+	Category []Facet `json:"category"`
+}
+
+// StoreSearch represents a page of a store search along with its facets.
+type StoreSearch struct {
+	Page[Store]
+	Facets *StoreFacets `json:"facets,omitempty"`
+}
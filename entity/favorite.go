@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Favorite represents data about an user following a store.
+type Favorite struct {
+	UserID    int       `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	StoreID   int       `json:"store_id" gorm:"primaryKey;autoIncrement:false"`
+	Store     *Store    `json:"store,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+// Store search orders.
+const (
+	SortName      = "name"
+	SortRating    = "rating"
+	SortRelevance = "relevance"
+	SortCreatedAt = "created_at"
+)
+
+// StoreFilter represents data about a store search.
+type StoreFilter struct {
+	Name       string
+	Terms      []string
+	OpenNow    bool
+	CategoryID int
+	Tag        string
+	State      string
+	City       string
+	Sort       string
+	Page       PageRequest
+	// AnyStatus includes the stores not approved yet, for the admin search.
+	AnyStatus bool
+}
+
+// NearbyFilter represents data about a search around a point.
+type NearbyFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
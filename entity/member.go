@@ -0,0 +1,30 @@
+package entity
+
+// Store roles, ordered from the most to the least privileged.
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleStaff   = "staff"
+)
+
+// StoreMember represents data about an user role inside a store.
+type StoreMember struct {
+	User    User   `json:"user" binding:"-"`
+	Store   *Store `json:"store,omitempty"`
+	ID      int    `json:"id" gorm:"primaryKey"`
+	StoreID int    `json:"store_id"`
+	UserID  int    `json:"user_id"`
+	Role    string `json:"role"`
+}
+
+// MemberInvite represents data about an invitation to join a store.
+type MemberInvite struct {
+	Email string `json:"email" binding:"required,email,max=50"`
+	Role  string `json:"role" binding:"required,oneof=manager staff"`
+}
+
+// SelfStores represents the stores an user belongs to and the selected one.
+type SelfStores struct {
+	Active *Store        `json:"active"`
+	Stores []StoreMember `json:"stores"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+// PageRequest represents data about the page of a listing being asked for,
+// Cursor is the NextCursor of the previous page.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// Page represents a page of a listing, an empty NextCursor means it's the last one.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// PhoneVerification represents data about a verification code sent to a phone.
+type PhoneVerification struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id"`
+	Phone     string    `json:"phone" binding:"required,max=20"`
+	Code      string    `json:"-"`
+	Attempts  int       `json:"attempts"`
+	Sends     int       `json:"sends"`
+	SentAt    time.Time `json:"sent_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PhoneCode represents data about a phone verification request.
+type PhoneCode struct {
+	Phone string `json:"phone" binding:"required,max=20"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
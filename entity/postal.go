@@ -0,0 +1,10 @@
+package entity
+
+// PostalAddress represents data about the address of a CEP.
+type PostalAddress struct {
+	ZipCode string `json:"zip_code"`
+	Address string `json:"address"`
+	Block   string `json:"block"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Notification types an user can opt in or out of.
+const (
+	NotifyStoreVerification = "store_verification"
+	NotifyNewReview         = "new_review"
+	NotifyReviewReply       = "review_reply"
+	NotifyNewFollower       = "new_follower"
+)
+
+// NotificationTypes lists every known notification type.
+var NotificationTypes = []string{
+	NotifyStoreVerification,
+	NotifyNewReview,
+	NotifyReviewReply,
+	NotifyNewFollower,
+}
+
+// Preferences represents the settings of an user.
+type Preferences struct {
+	UserID        int           `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Locale        string        `json:"locale" binding:"omitempty,max=35"`
+	Timezone      string        `json:"timezone" binding:"omitempty,max=64"`
+	Notifications Notifications `json:"notifications"`
+	Marketing     bool          `json:"marketing"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+func (Preferences) TableName() string {
+	return "user_preferences"
+}
+
+// NotificationChannels tells the channels enabled for a notification type.
+type NotificationChannels struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+}
+
+// Notifications maps the notification types to their channels, stored as JSON.
+type Notifications map[string]NotificationChannels
+
+func (n Notifications) Value() (driver.Value, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (n *Notifications) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported notifications type %T", value)
+	}
+
+	*n = Notifications{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, n)
+}
+
+// PreferencesPatch holds the preferences to change, nil fields are kept.
+type PreferencesPatch struct {
+	Locale        *string                              `json:"locale" binding:"omitempty,max=35"`
+	Timezone      *string                              `json:"timezone" binding:"omitempty,max=64"`
+	Notifications map[string]NotificationChannelsPatch `json:"notifications"`
+	Marketing     *bool                                `json:"marketing"`
+}
+
+// NotificationChannelsPatch holds the channels to change of a notification type.
+type NotificationChannelsPatch struct {
+	Email *bool `json:"email"`
+	SMS   *bool `json:"sms"`
+}
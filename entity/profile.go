@@ -2,13 +2,20 @@ package entity
 
 // Profile represents data about an profile.
 type Profile struct {
-	User    User   `json:"user"`
-	ID      int    `json:"id" gorm:"primaryKey"`
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	Block   string `json:"block"`
-	ZipCode string `json:"zip_code"`
-	City    string `json:"city"`
-	State   string `json:"state"`
-	UserID  int    `json:"user_id"`
+	User          User              `json:"user" binding:"-"`
+	ID            int               `json:"id" gorm:"primaryKey"`
+	Name          string            `json:"name" binding:"required,max=100"`
+	CPF           *string           `json:"cpf,omitempty" binding:"omitempty,max=14"`
+	Phone         string            `json:"phone" binding:"max=20"`
+	PhoneVerified bool              `json:"phone_verified"`
+	Address       string            `json:"address" binding:"max=100"`
+	Block         string            `json:"block" binding:"max=100"`
+	ZipCode       string            `json:"zip_code" binding:"max=9"`
+	City          string            `json:"city" binding:"max=100"`
+	State         string            `json:"state" binding:"max=100"`
+	AvatarPath    string            `json:"avatar_path"`
+	AvatarURL     string            `json:"avatar_url,omitempty" gorm:"-"`
+	AvatarURLs    map[string]string `json:"avatar_urls,omitempty" gorm:"-"`
+	Version       int               `json:"version" gorm:"default:1"`
+	UserID        int               `json:"user_id"`
 }
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Review represents data about a buyer review of a store.
+type Review struct {
+	ID        int        `json:"id" gorm:"primaryKey"`
+	StoreID   int        `json:"store_id"`
+	UserID    int        `json:"user_id"`
+	Rating    int        `json:"rating" binding:"gte=1,lte=5"`
+	Text      string     `json:"text" binding:"max=2000"`
+	Reply     string     `json:"reply,omitempty"`
+	RepliedAt *time.Time `json:"replied_at,omitempty"`
+	Hidden    bool       `json:"hidden"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ReviewReply represents data about the store reply to a review.
+type ReviewReply struct {
+	Reply string `json:"reply" binding:"max=2000"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// StoreHours represents data about an opening interval of a store on a weekday,
+// times are `15:04` in the store timezone and Weekday 0 is Sunday.
+type StoreHours struct {
+	ID      int    `json:"-" gorm:"primaryKey"`
+	StoreID int    `json:"-"`
+	Weekday int    `json:"weekday" binding:"gte=0,lte=6"`
+	Opens   string `json:"opens" binding:"omitempty,len=5"`
+	Closes  string `json:"closes" binding:"omitempty,len=5"`
+}
+
+// StoreHoliday represents data about a day when the store doesn't follow its
+// weekly hours, it's either closed or open from Opens to Closes. Date is
+// `2006-01-02` and kept as text, so it reads back as written.
+type StoreHoliday struct {
+	ID      int    `json:"-" gorm:"primaryKey"`
+	StoreID int    `json:"-"`
+	Date    string `json:"date" binding:"required,len=10"`
+	Closed  bool   `json:"closed"`
+	Opens   string `json:"opens,omitempty" binding:"omitempty,len=5"`
+	Closes  string `json:"closes,omitempty" binding:"omitempty,len=5"`
+	Note    string `json:"note,omitempty" binding:"max=100"`
+}
+
+// StoreSchedule represents data about when a store operates.
+type StoreSchedule struct {
+	Timezone string         `json:"timezone" binding:"max=50"`
+	Vacation bool           `json:"vacation"`
+	ReopenAt *time.Time     `json:"reopen_at"`
+	Hours    []StoreHours   `json:"hours" binding:"max=50,dive"`
+	Holidays []StoreHoliday `json:"holidays" binding:"max=366,dive"`
+	Version  int            `json:"version"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// StoreSlug represents an old slug of a store, kept to redirect old links.
+type StoreSlug struct {
+	Slug      string    `json:"slug" gorm:"primaryKey"`
+	StoreID   int       `json:"store_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
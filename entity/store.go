@@ -1,14 +1,57 @@
 package entity
 
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Store verification statuses, only approved stores are public.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
 // Store represents data about an store.
 type Store struct {
-	User      User   `json:"user"`
-	ID        int    `json:"id" gorm:"primaryKey"`
-	Name      string `json:"name"`
-	Address   string `json:"address"`
-	Block     string `json:"block"`
-	City      string `json:"city"`
-	State     string `json:"state"`
-	PhotoPath string `json:"photo_path"`
-	UserID    int    `json:"user_id"`
+	User            User              `json:"user" binding:"-"`
+	ID              int               `json:"id" gorm:"primaryKey"`
+	Name            string            `json:"name" binding:"required,max=100"`
+	Description     string            `json:"description" binding:"max=2000"`
+	Slug            string            `json:"slug"`
+	CNPJ            *string           `json:"cnpj,omitempty" binding:"omitempty,max=18"`
+	CPF             string            `json:"cpf,omitempty" binding:"max=14"`
+	Phone           string            `json:"phone" binding:"max=20"`
+	PhoneVerified   bool              `json:"phone_verified"`
+	Address         string            `json:"address" binding:"max=100"`
+	Block           string            `json:"block" binding:"max=100"`
+	ZipCode         string            `json:"zip_code" binding:"max=9"`
+	City            string            `json:"city" binding:"max=100"`
+	State           string            `json:"state" binding:"max=100"`
+	PhotoPath       string            `json:"photo_path" binding:"max=100"`
+	Latitude        *float64          `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude       *float64          `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+	SearchText      string            `json:"-"`
+	Relevance       float64           `json:"relevance,omitempty" gorm:"->"`
+	Highlights      map[string]string `json:"highlights,omitempty" gorm:"-"`
+	Distance        *float64          `json:"distance_km,omitempty" gorm:"-"`
+	Categories      []Category        `json:"categories" gorm:"many2many:store_categories"`
+	Tags            Tags              `json:"tags" binding:"max=20,dive,max=30"`
+	RatingAvg       float64           `json:"rating"`
+	RatingCount     int               `json:"rating_count"`
+	FollowerCount   int64             `json:"follower_count" gorm:"-"`
+	Status          string            `json:"status"`
+	RejectionReason string            `json:"rejection_reason,omitempty"`
+	SubmittedAt     *time.Time        `json:"submitted_at"`
+	ReviewedAt      *time.Time        `json:"reviewed_at"`
+	Version         int               `json:"version" gorm:"default:1"`
+	UserID          int               `json:"user_id"`
+	Timezone        string            `json:"timezone" binding:"max=50"`
+	Vacation        bool              `json:"vacation"`
+	ReopenAt        *time.Time        `json:"reopen_at"`
+	Hours           []StoreHours      `json:"hours,omitempty" binding:"max=50,dive"`
+	Holidays        []StoreHoliday    `json:"holidays,omitempty" binding:"max=366,dive"`
+	OpenNow         bool              `json:"open_now" gorm:"-"`
+	CreatedAt       time.Time         `json:"created_at"`
+	ArchivedAt      gorm.DeletedAt    `json:"archived_at"`
 }
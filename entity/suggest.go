@@ -0,0 +1,8 @@
+package entity
+
+// StoreSuggestion represents a store offered while the user types a search.
+type StoreSuggestion struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
@@ -1,9 +1,12 @@
 package entity
 
+import "time"
+
 // User represents data about an user.
 type User struct {
-	ID       int    `json:"id" gorm:"primaryKey"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	IsAdmin  bool   `json:"is_admin"`
+	ID        int        `json:"id" gorm:"primaryKey"`
+	Email     string     `json:"email" binding:"required,email,max=50"`
+	Password  string     `json:"password" binding:"required,max=72"`
+	IsAdmin   bool       `json:"is_admin"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
 }
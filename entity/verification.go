@@ -0,0 +1,6 @@
+package entity
+
+// StoreRejection represents data about an admin rejecting a store.
+type StoreRejection struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
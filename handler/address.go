@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+)
+
+// GetSelfAddresses lists the user Addresses.
+func (u *User) GetSelfAddresses(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	result, err := u.controller.GetSelfAddresses(ctx)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// CreateSelfAddress creates a new user Address.
+func (u *User) CreateSelfAddress(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	var address entity.Address
+	if !bindJSON(c, &address) {
+		return
+	}
+
+	err := u.controller.CreateSelfAddress(ctx, &address)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, address)
+}
+
+// UpdateSelfAddress updates an user Address.
+func (u *User) UpdateSelfAddress(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	var address entity.Address
+	if !bindJSON(c, &address) {
+		return
+	}
+
+	err := u.controller.UpdateSelfAddress(ctx, id, &address)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, address)
+}
+
+// DeleteSelfAddress deletes an user Address.
+func (u *User) DeleteSelfAddress(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.DeleteSelfAddress(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
+
+// LookupAddress finds the Address of a CEP.
+func (u *User) LookupAddress(c *gin.Context) {
+	result, err := u.controller.LookupAddress(c.Request.Context(), c.Param("cep"))
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
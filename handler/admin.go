@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+	"time"
+)
+
+// SearchUsers search the Users with their Profiles and Stores.
+func (u *User) SearchUsers(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	filter, err := userFilter(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	result, err := u.controller.SearchUsers(ctx, filter)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DebugVars serves the expvar counters, like the cache hits, to admins.
+func (u *User) DebugVars(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	err := u.controller.RequireAdmin(ctx)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// userFilter reads the admin user search query, `created_from` and
+// `created_to` are inclusive YYYY-MM-DD dates.
+func userFilter(c *gin.Context) (*entity.UserFilter, error) {
+	filter := &entity.UserFilter{
+		EmailPrefix: c.Query("email"),
+		Role:        c.Query("role"),
+		Status:      c.Query("status"),
+		City:        c.Query("city"),
+		State:       c.Query("state"),
+	}
+
+	switch filter.Role {
+	case "", entity.RoleAdmin, entity.RoleCustomer, entity.RoleOwner, entity.RoleManager, entity.RoleStaff:
+	default:
+		return nil, errors.New("invalid role")
+	}
+	switch filter.Status {
+	case "", entity.StatusPending, entity.StatusApproved, entity.StatusRejected, entity.StatusArchived:
+	default:
+		return nil, errors.New("invalid status")
+	}
+
+	if from := c.Query("created_from"); from != "" {
+		date, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return nil, errors.New("invalid created_from")
+		}
+		filter.CreatedFrom = &date
+	}
+	if to := c.Query("created_to"); to != "" {
+		date, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return nil, errors.New("invalid created_to")
+		}
+		date = date.AddDate(0, 0, 1)
+		filter.CreatedTo = &date
+	}
+
+	page, err := pageRequest(c)
+	if err != nil {
+		return nil, err
+	}
+	filter.Page = page
+
+	return filter, nil
+}
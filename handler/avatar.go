@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"net/http"
+)
+
+// UpdateSelfAvatar uploads a new avatar for the logged Profile.
+func (u *User) UpdateSelfAvatar(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	data, err := file.Open()
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+	defer data.Close()
+
+	result, err := u.controller.UpdateSelfAvatar(ctx, data)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DeleteSelfAvatar removes the avatar of the logged Profile.
+func (u *User) DeleteSelfAvatar(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	err := u.controller.DeleteSelfAvatar(ctx)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
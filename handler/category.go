@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+)
+
+// GetCategories lists the Store categories.
+func (u *User) GetCategories(c *gin.Context) {
+	result, err := u.controller.GetCategories(c.Request.Context())
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// CreateCategory creates a new Category.
+func (u *User) CreateCategory(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	var category entity.Category
+	if !bindJSON(c, &category) {
+		return
+	}
+
+	err := u.controller.CreateCategory(ctx, &category)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, category)
+}
+
+// UpdateCategory updates a Category.
+func (u *User) UpdateCategory(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	var category entity.Category
+	if !bindJSON(c, &category) {
+		return
+	}
+
+	err := u.controller.UpdateCategory(ctx, id, &category)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, category)
+}
+
+// DeleteCategory deletes a Category.
+func (u *User) DeleteCategory(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.DeleteCategory(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag tags the response with the version of the returned record.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.Itoa(version)))
+}
+
+// ifMatch reads the version required by the If-Match header, `*` matches any
+// version and is returned as zero. It answers the request and returns false
+// when the header is missing or can't match.
+func ifMatch(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.IndentedJSON(http.StatusPreconditionRequired, struct {
+			Error string
+		}{
+			"If-Match header is required",
+		})
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || version <= 0 || !strings.HasPrefix(header, `"`) {
+		c.IndentedJSON(http.StatusPreconditionFailed, struct {
+			Error string
+		}{
+			"If-Match doesn't match the current version",
+		})
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"net/http"
+)
+
+// AddFavorite follows a Store.
+func (u *User) AddFavorite(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("storeID")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.AddFavorite(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
+
+// RemoveFavorite unfollows a Store.
+func (u *User) RemoveFavorite(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("storeID")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.RemoveFavorite(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
+
+// GetFavorites lists the followed Stores.
+func (u *User) GetFavorites(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	page, err := pageRequest(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	result, err := u.controller.GetFavorites(ctx, page)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
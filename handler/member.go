@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+)
+
+// InviteMember adds an user to a Store.
+func (u *User) InviteMember(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	var invite entity.MemberInvite
+	if !bindJSON(c, &invite) {
+		return
+	}
+
+	result, err := u.controller.InviteMember(ctx, id, &invite)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// GetMembers lists the Store members.
+func (u *User) GetMembers(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	result, err := u.controller.GetMembers(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// RemoveMember removes an user from a Store.
+func (u *User) RemoveMember(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	userID := c.Param("userID")
+	if id == "" || userID == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.RemoveMember(ctx, id, userID)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+)
+
+// SendPhoneCode sends a verification code to a Phone.
+func (u *User) SendPhoneCode(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	var verification entity.PhoneVerification
+	if !bindJSON(c, &verification) {
+		return
+	}
+
+	err := u.controller.SendPhoneCode(ctx, verification.Phone)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusAccepted, struct{}{})
+}
+
+// ConfirmPhoneCode verifies a Phone with its code.
+func (u *User) ConfirmPhoneCode(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	var code entity.PhoneCode
+	if !bindJSON(c, &code) {
+		return
+	}
+
+	err := u.controller.ConfirmPhoneCode(ctx, &code)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
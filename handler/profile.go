@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+)
+
+// mergePatchType is the RFC 7396 media type, plain JSON is accepted as well.
+const mergePatchType = "application/merge-patch+json"
+
+// PatchProfile changes the fields of a Profile given in a JSON Merge Patch.
+func (u *User) PatchProfile(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	if contentType := c.ContentType(); contentType != mergePatchType && contentType != gin.MIMEJSON {
+		c.IndentedJSON(http.StatusUnsupportedMediaType, struct {
+			Error string
+		}{
+			"expected " + mergePatchType,
+		})
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	if !validatePatch(c, patch, &entity.Profile{}) {
+		return
+	}
+
+	result, err := u.controller.PatchProfile(ctx, id, version, patch)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	setETag(c, result.Version)
+	c.IndentedJSON(http.StatusOK, result)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"github.com/restore/user/entity"
+	"net/http"
+)
+
+// SaveReview creates or edits the user Review of a Store.
+func (u *User) SaveReview(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	var review entity.Review
+	if !bindJSON(c, &review) {
+		return
+	}
+
+	err := u.controller.SaveReview(ctx, id, &review)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, review)
+}
+
+// GetReviews lists the Store Reviews.
+func (u *User) GetReviews(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	page, err := pageRequest(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	result, err := u.controller.GetReviews(c.Request.Context(), id, page)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// ReplyReview replies to a Review as the Store.
+func (u *User) ReplyReview(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	reviewID := c.Param("reviewID")
+	if id == "" || reviewID == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	var reply entity.ReviewReply
+	if !bindJSON(c, &reply) {
+		return
+	}
+
+	err := u.controller.ReplyReview(ctx, id, reviewID, &reply)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, reply)
+}
+
+// HideReview hides a Review from the Store page.
+func (u *User) HideReview(c *gin.Context) {
+	u.moderateReview(c, true)
+}
+
+// ShowReview shows a hidden Review again.
+func (u *User) ShowReview(c *gin.Context) {
+	u.moderateReview(c, false)
+}
+
+func (u *User) moderateReview(c *gin.Context, hidden bool) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.HideReview(ctx, id, hidden)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
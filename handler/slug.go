@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/restore/user/config"
+	"net/http"
+	"net/url"
+)
+
+// GetStoreBySlug gets a Store by its slug, old slugs are redirected to the current one.
+func (u *User) GetStoreBySlug(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	slug := c.Param("slug")
+	if slug == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid slug",
+		})
+		return
+	}
+
+	result, moved, err := u.controller.GetStoreBySlug(ctx, slug)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	if moved {
+		c.Redirect(http.StatusMovedPermanently, "/store/by-slug/"+url.PathEscape(result.Slug))
+		return
+	}
+
+	setETag(c, result.Version)
+	c.IndentedJSON(http.StatusOK, result)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// SuggestStores lists the Stores whose name starts with the typed text.
+func (u *User) SuggestStores(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			c.IndentedJSON(http.StatusBadRequest, struct {
+				Error string
+			}{
+				"invalid limit",
+			})
+			return
+		}
+		limit = n
+	}
+
+	result := u.controller.SuggestStores(c.Request.Context(), c.Query("q"), limit)
+	c.IndentedJSON(http.StatusOK, result)
+}
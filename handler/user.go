@@ -2,10 +2,15 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/restore/user/config"
 	"github.com/restore/user/entity"
+	"gorm.io/gorm"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type controller interface {
@@ -14,11 +19,54 @@ type controller interface {
 	Login(ctx context.Context, user *entity.User) (string, bool, error)
 	GetProfile(ctx context.Context, id string) (*entity.Profile, error)
 	GetStore(ctx context.Context, id string) (*entity.Store, error)
-	SearchStore(ctx context.Context, name string) ([]entity.Store, error)
-	UpdateProfile(ctx context.Context, id string, profile *entity.Profile) error
+	SearchStore(ctx context.Context, filter *entity.StoreFilter) (*entity.StoreSearch, error)
+	SuggestStores(ctx context.Context, prefix string, limit int) []entity.StoreSuggestion
+	UpdateProfile(ctx context.Context, id string, version int, profile *entity.Profile) error
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetSelfProfile(ctx context.Context) (*entity.Profile, error)
-	GetSelfStore(ctx context.Context) (*entity.Store, error)
+	GetSelfStore(ctx context.Context, active string) (*entity.SelfStores, error)
+	DeleteStore(ctx context.Context, id string) error
+	RestoreStore(ctx context.Context, id string) error
+	InviteMember(ctx context.Context, id string, invite *entity.MemberInvite) (*entity.StoreMember, error)
+	GetMembers(ctx context.Context, id string) ([]entity.StoreMember, error)
+	RemoveMember(ctx context.Context, id string, userID string) error
+	GetSelfAddresses(ctx context.Context) ([]entity.Address, error)
+	CreateSelfAddress(ctx context.Context, address *entity.Address) error
+	UpdateSelfAddress(ctx context.Context, id string, address *entity.Address) error
+	DeleteSelfAddress(ctx context.Context, id string) error
+	GetDefaultAddress(ctx context.Context, email string) (*entity.Address, error)
+	UpdateStore(ctx context.Context, id string, version int, store *entity.Store) error
+	LookupAddress(ctx context.Context, cep string) (*entity.PostalAddress, error)
+	SendPhoneCode(ctx context.Context, phone string) error
+	ConfirmPhoneCode(ctx context.Context, code *entity.PhoneCode) error
+	UpdateSchedule(ctx context.Context, id string, version int, schedule *entity.StoreSchedule) error
+	NearbyStores(ctx context.Context, filter *entity.NearbyFilter) ([]entity.Store, error)
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+	CreateCategory(ctx context.Context, category *entity.Category) error
+	UpdateCategory(ctx context.Context, id string, category *entity.Category) error
+	DeleteCategory(ctx context.Context, id string) error
+	SaveReview(ctx context.Context, id string, review *entity.Review) error
+	GetReviews(ctx context.Context, id string, page entity.PageRequest) (*entity.Page[entity.Review], error)
+	ReplyReview(ctx context.Context, id string, reviewID string, reply *entity.ReviewReply) error
+	HideReview(ctx context.Context, reviewID string, hidden bool) error
+	AddFavorite(ctx context.Context, id string) error
+	RemoveFavorite(ctx context.Context, id string) error
+	GetFavorites(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Favorite], error)
+	GetStoreFollowers(ctx context.Context, id string) ([]entity.User, error)
+	GetReviewQueue(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Store], error)
+	ApproveStore(ctx context.Context, id string) error
+	RejectStore(ctx context.Context, id string, rejection *entity.StoreRejection) error
+	ResubmitStore(ctx context.Context, id string) error
+	GetStoreBySlug(ctx context.Context, slug string) (*entity.Store, bool, error)
+	UpdateSelfAvatar(ctx context.Context, r io.Reader) (*entity.Profile, error)
+	DeleteSelfAvatar(ctx context.Context) error
+	GetUserDetails(ctx context.Context, email string) (*entity.Profile, error)
+	GetSelfPreferences(ctx context.Context) (*entity.Preferences, error)
+	GetUserPreferences(ctx context.Context, email string) (*entity.Preferences, error)
+	UpdateSelfPreferences(ctx context.Context, patch *entity.PreferencesPatch) (*entity.Preferences, error)
+	PatchProfile(ctx context.Context, id string, version int, patch []byte) (*entity.Profile, error)
+	SearchUsers(ctx context.Context, filter *entity.UserFilter) (*entity.Page[entity.UserSummary], error)
+	RequireAdmin(ctx context.Context) error
 }
 
 type User struct {
@@ -34,12 +82,7 @@ func NewUser(c controller) *User {
 // Register creates a new User.
 func (u *User) Register(c *gin.Context) {
 	var profile entity.Profile
-	if err := c.BindJSON(&profile); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
-			Error string
-		}{
-			err.Error(),
-		})
+	if !bindJSON(c, &profile, nested{"user", &profile.User}) {
 		return
 	}
 
@@ -65,12 +108,7 @@ func (u *User) RegisterStore(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
 
 	var store entity.Store
-	if err := c.BindJSON(&store); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
-			Error string
-		}{
-			err.Error(),
-		})
+	if !bindJSON(c, &store, nested{"user", &store.User}) {
 		return
 	}
 
@@ -94,12 +132,7 @@ func (u *User) RegisterStore(c *gin.Context) {
 // Login creates a new User session.
 func (u *User) Login(c *gin.Context) {
 	var user entity.User
-	if err := c.BindJSON(&user); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
-			Error string
-		}{
-			err.Error(),
-		})
+	if !bindJSON(c, &user) {
 		return
 	}
 
@@ -146,6 +179,7 @@ func (u *User) GetProfile(c *gin.Context) {
 		return
 	}
 
+	setETag(c, result.Version)
 	c.IndentedJSON(http.StatusCreated, result)
 }
 
@@ -165,7 +199,7 @@ func (u *User) GetStore(c *gin.Context) {
 
 	result, err := u.controller.GetStore(ctx, id)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
+		c.IndentedJSON(errorStatus(err), struct {
 			Error string
 		}{
 			err.Error(),
@@ -173,29 +207,105 @@ func (u *User) GetStore(c *gin.Context) {
 		return
 	}
 
+	setETag(c, result.Version)
 	c.IndentedJSON(http.StatusCreated, result)
 }
 
-// SearchStore search a Store.
-func (u *User) SearchStore(c *gin.Context) {
-	name := c.Param("name")
-	result, err := u.controller.SearchStore(c.Request.Context(), name)
+// UpdateStore updates a Store.
+func (u *User) UpdateStore(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	var store entity.Store
+	if !bindJSON(c, &store) {
+		return
+	}
+
+	err := u.controller.UpdateStore(ctx, id, version, &store)
 	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	setETag(c, store.Version)
+	c.IndentedJSON(http.StatusOK, store)
+}
+
+// UpdateSchedule updates the Store hours, holidays and vacation mode.
+func (u *User) UpdateSchedule(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
 		c.IndentedJSON(http.StatusBadRequest, struct {
 			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	var schedule entity.StoreSchedule
+	if !bindJSON(c, &schedule) {
+		return
+	}
+
+	err := u.controller.UpdateSchedule(ctx, id, version, &schedule)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
 		}{
 			err.Error(),
 		})
 		return
 	}
 
-	c.IndentedJSON(http.StatusCreated, result)
+	setETag(c, schedule.Version)
+	c.IndentedJSON(http.StatusOK, schedule)
+}
+
+// SearchStore search a Store.
+func (u *User) SearchStore(c *gin.Context) {
+	u.searchStore(c, c.Param("name"), false)
 }
 
-// SearchAdminStore search a Store.
+// FilterStore search a Store by the `name` query.
+func (u *User) FilterStore(c *gin.Context) {
+	u.searchStore(c, c.Query("name"), false)
+}
+
+// SearchAdminStore search a Store in any verification status.
 func (u *User) SearchAdminStore(c *gin.Context) {
-	name := c.Query("name")
-	result, err := u.controller.SearchStore(c.Request.Context(), name)
+	u.searchStore(c, c.Query("name"), true)
+}
+
+func (u *User) searchStore(c *gin.Context, name string, anyStatus bool) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	filter, err := storeFilter(c, name)
 	if err != nil {
 		c.IndentedJSON(http.StatusBadRequest, struct {
 			Error string
@@ -204,10 +314,59 @@ func (u *User) SearchAdminStore(c *gin.Context) {
 		})
 		return
 	}
+	filter.AnyStatus = anyStatus
+
+	result, err := u.controller.SearchStore(ctx, filter)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
 
 	c.IndentedJSON(http.StatusCreated, result)
 }
 
+// NearbyStores search the Stores around `lat` and `lng` inside `radius_km`.
+func (u *User) NearbyStores(c *gin.Context) {
+	var filter entity.NearbyFilter
+	var err error
+	params := map[string]*float64{
+		"lat":       &filter.Latitude,
+		"lng":       &filter.Longitude,
+		"radius_km": &filter.RadiusKm,
+	}
+	for name, value := range params {
+		param := c.Query(name)
+		if param == "" && name == "radius_km" {
+			continue
+		}
+		*value, err = strconv.ParseFloat(param, 64)
+		if err != nil || math.IsNaN(*value) || math.IsInf(*value, 0) {
+			c.IndentedJSON(http.StatusBadRequest, struct {
+				Error string
+			}{
+				"invalid " + name,
+			})
+			return
+		}
+	}
+
+	result, err := u.controller.NearbyStores(c.Request.Context(), &filter)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
 // UpdateProfile updates a Profile.
 func (u *User) UpdateProfile(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
@@ -222,19 +381,19 @@ func (u *User) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
 	var profile entity.Profile
-	if err := c.BindJSON(&profile); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
-			Error string
-		}{
-			err.Error(),
-		})
+	if !bindJSON(c, &profile) {
 		return
 	}
 
-	err := u.controller.UpdateProfile(ctx, id, &profile)
+	err := u.controller.UpdateProfile(ctx, id, version, &profile)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
+		c.IndentedJSON(errorStatus(err), struct {
 			Error string
 		}{
 			err.Error(),
@@ -242,6 +401,7 @@ func (u *User) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	setETag(c, profile.Version)
 	c.IndentedJSON(http.StatusCreated, profile)
 }
 
@@ -259,16 +419,17 @@ func (u *User) GetSelfProfile(c *gin.Context) {
 		return
 	}
 
+	setETag(c, result.Version)
 	c.IndentedJSON(http.StatusOK, result)
 }
 
-// GetSelfStore finds the user Store.
+// GetSelfStore finds the user Stores, `store` query selects the active one.
 func (u *User) GetSelfStore(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
 
-	result, err := u.controller.GetSelfStore(ctx)
+	result, err := u.controller.GetSelfStore(ctx, c.Query("store"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, struct {
+		c.IndentedJSON(errorStatus(err), struct {
 			Error string
 		}{
 			err.Error(),
@@ -278,3 +439,130 @@ func (u *User) GetSelfStore(c *gin.Context) {
 
 	c.IndentedJSON(http.StatusOK, result)
 }
+
+// DeleteStore archives a Store.
+func (u *User) DeleteStore(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.DeleteStore(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
+
+// RestoreStore restores an archived Store.
+func (u *User) RestoreStore(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), config.EmailHeader, c.GetHeader(config.EmailHeader))
+
+	id := c.Param("id")
+	if id == "" {
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			"invalid ID",
+		})
+		return
+	}
+
+	err := u.controller.RestoreStore(ctx, id)
+	if err != nil {
+		c.IndentedJSON(errorStatus(err), struct {
+			Error string
+		}{
+			err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, struct{}{})
+}
+
+// storeFilter reads the store search filters from the query.
+func storeFilter(c *gin.Context, name string) (*entity.StoreFilter, error) {
+	filter := &entity.StoreFilter{
+		Name:    name,
+		OpenNow: c.Query("open_now") == "true",
+		Tag:     c.Query("tag"),
+		State:   c.Query("state"),
+		City:    c.Query("city"),
+		Sort:    c.Query("sort"),
+	}
+
+	if filter.Sort != "" && filter.Sort != entity.SortName && filter.Sort != entity.SortRating &&
+		filter.Sort != entity.SortRelevance && filter.Sort != entity.SortCreatedAt {
+		return nil, errors.New("invalid sort")
+	}
+
+	page, err := pageRequest(c)
+	if err != nil {
+		return nil, err
+	}
+	filter.Page = page
+
+	if category := c.Query("category"); category != "" {
+		id, err := strconv.Atoi(category)
+		if err != nil {
+			return nil, errors.New("invalid category")
+		}
+		filter.CategoryID = id
+	}
+
+	return filter, nil
+}
+
+// pageRequest reads the `limit` and `cursor` query of a listing.
+func pageRequest(c *gin.Context) (entity.PageRequest, error) {
+	page := entity.PageRequest{
+		Cursor: c.Query("cursor"),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return page, errors.New("invalid limit")
+		}
+		page.Limit = n
+	}
+
+	return page, nil
+}
+
+// errorStatus maps controller errors to HTTP status codes.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, entity.ErrCEPNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrOwnerRemoval), errors.Is(err, entity.ErrInvalidStatus),
+		errors.Is(err, gorm.ErrDuplicatedKey):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrStoreArchived), errors.Is(err, entity.ErrRetentionExpired):
+		return http.StatusGone
+	case errors.Is(err, entity.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, entity.ErrTooManyCodes):
+		return http.StatusTooManyRequests
+	case errors.Is(err, entity.ErrSMSUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -19,13 +19,79 @@ func NewUserServer(c controller) *UserServer {
 	}
 }
 
+// GetUser finds an user along with its profile name and avatar.
 func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
-	user, err := s.controller.GetUserByEmail(ctx, req.Email)
+	profile, err := s.controller.GetUserDetails(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.GetUserResponse{
-		Id:      strconv.Itoa(user.ID),
-		IsAdmin: user.IsAdmin,
+		Id:         strconv.Itoa(profile.User.ID),
+		IsAdmin:    profile.User.IsAdmin,
+		Name:       profile.Name,
+		AvatarUrl:  profile.AvatarURL,
+		AvatarUrls: profile.AvatarURLs,
+	}, nil
+}
+
+// GetDefaultAddress finds the default shipping address of an user.
+func (s *UserServer) GetDefaultAddress(ctx context.Context, req *pb.GetDefaultAddressRequest) (*pb.Address, error) {
+	address, err := s.controller.GetDefaultAddress(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Address{
+		Id:        strconv.Itoa(address.ID),
+		Label:     address.Label,
+		Address:   address.Address,
+		Block:     address.Block,
+		ZipCode:   address.ZipCode,
+		City:      address.City,
+		State:     address.State,
+		UserId:    strconv.Itoa(address.UserID),
+		IsDefault: address.DefaultShipping,
+	}, nil
+}
+
+// GetStoreFollowers lists the users following a store.
+func (s *UserServer) GetStoreFollowers(ctx context.Context, req *pb.GetStoreFollowersRequest) (*pb.GetStoreFollowersResponse, error) {
+	users, err := s.controller.GetStoreFollowers(ctx, req.StoreId)
+	if err != nil {
+		return nil, err
+	}
+
+	followers := make([]*pb.Follower, 0, len(users))
+	for _, user := range users {
+		followers = append(followers, &pb.Follower{
+			Id:    strconv.Itoa(user.ID),
+			Email: user.Email,
+		})
+	}
+	return &pb.GetStoreFollowersResponse{
+		StoreId:   req.StoreId,
+		Followers: followers,
+	}, nil
+}
+
+// GetPreferences finds the preferences of an user.
+func (s *UserServer) GetPreferences(ctx context.Context, req *pb.GetPreferencesRequest) (*pb.Preferences, error) {
+	preferences, err := s.controller.GetUserPreferences(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make(map[string]*pb.NotificationChannels, len(preferences.Notifications))
+	for kind, channels := range preferences.Notifications {
+		notifications[kind] = &pb.NotificationChannels{
+			Email: channels.Email,
+			Sms:   channels.SMS,
+		}
+	}
+	return &pb.Preferences{
+		Email:         req.Email,
+		Locale:        preferences.Locale,
+		Timezone:      preferences.Timezone,
+		Notifications: notifications,
+		Marketing:     preferences.Marketing,
 	}, nil
 }
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes an invalid request field, Code is meant for clients
+// and Message for people.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// nested is a part of the request validated on its own, like the user of a
+// profile registration, which isn't sent on updates.
+type nested struct {
+	field string
+	value interface{}
+}
+
+// fieldCodes maps the validation tags to the codes returned to clients.
+var fieldCodes = map[string]string{
+	"required": "required",
+	"email":    "invalid_email",
+	"max":      "too_long",
+	"min":      "too_short",
+	"len":      "invalid_length",
+	"oneof":    "invalid_choice",
+	"gte":      "out_of_range",
+	"lte":      "out_of_range",
+}
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// bindJSON decodes and validates the request body along with its nested
+// parts. It answers the request and returns false when the body is invalid,
+// with 422 listing the invalid fields.
+func bindJSON(c *gin.Context, obj interface{}, parts ...nested) bool {
+	var fields []FieldError
+
+	err := c.ShouldBindJSON(obj)
+	var validation validator.ValidationErrors
+	if err != nil && !errors.As(err, &validation) {
+		return invalidRequest(c, err, "")
+	}
+	fields = appendFieldErrors(fields, validation, "")
+
+	for _, part := range parts {
+		err = binding.Validator.ValidateStruct(part.value)
+		validation = nil
+		if err != nil && !errors.As(err, &validation) {
+			return invalidRequest(c, err, part.field)
+		}
+		fields = appendFieldErrors(fields, validation, part.field)
+	}
+
+	if len(fields) > 0 {
+		unprocessable(c, fields)
+		return false
+	}
+	return true
+}
+
+// validateJSON validates a body decoded by the handler itself.
+func validateJSON(c *gin.Context, obj interface{}) bool {
+	err := binding.Validator.ValidateStruct(obj)
+	if err != nil {
+		return invalidRequest(c, err, "")
+	}
+	return true
+}
+
+// validatePatch validates the members of a JSON Merge Patch against the rules
+// of the patched type, members set to null are checked as zero values.
+func validatePatch(c *gin.Context, patch []byte, obj interface{}) bool {
+	var members map[string]json.RawMessage
+	err := json.Unmarshal(patch, &members)
+	if err != nil {
+		return invalidRequest(c, err, "")
+	}
+	err = json.Unmarshal(patch, obj)
+	if err != nil {
+		return invalidRequest(c, err, "")
+	}
+
+	var fields []string
+	typ := reflect.Indirect(reflect.ValueOf(obj)).Type()
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.SplitN(typ.Field(i).Tag.Get("json"), ",", 2)[0]
+		if _, ok := members[name]; ok {
+			fields = append(fields, typ.Field(i).Name)
+		}
+	}
+	if len(fields) == 0 {
+		return true
+	}
+
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return true
+	}
+	err = v.StructPartial(obj, fields...)
+	if err != nil {
+		return invalidRequest(c, err, "")
+	}
+	return true
+}
+
+func invalidRequest(c *gin.Context, err error, prefix string) bool {
+	var validation validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validation):
+		unprocessable(c, appendFieldErrors(nil, validation, prefix))
+	case errors.As(err, &typeErr):
+		unprocessable(c, []FieldError{{
+			Field:   joinField(prefix, typeErr.Field),
+			Code:    "invalid_type",
+			Message: fmt.Sprintf("must be a %s", typeErr.Type.Kind()),
+		}})
+	default:
+		c.IndentedJSON(http.StatusBadRequest, struct {
+			Error string
+		}{
+			err.Error(),
+		})
+	}
+	return false
+}
+
+func unprocessable(c *gin.Context, fields []FieldError) {
+	c.IndentedJSON(http.StatusUnprocessableEntity, struct {
+		Error  string
+		Fields []FieldError
+	}{
+		"invalid request",
+		fields,
+	})
+}
+
+func appendFieldErrors(fields []FieldError, validation validator.ValidationErrors, prefix string) []FieldError {
+	for _, e := range validation {
+		fields = append(fields, fieldError(e, prefix))
+	}
+	return fields
+}
+
+func fieldError(e validator.FieldError, prefix string) FieldError {
+	// The namespace starts with the struct type, which isn't part of the body.
+	field := e.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+
+	code, ok := fieldCodes[e.Tag()]
+	if !ok {
+		code = "invalid"
+	}
+
+	return FieldError{
+		Field:   joinField(prefix, field),
+		Code:    code,
+		Message: fieldMessage(e),
+	}
+}
+
+func fieldMessage(e validator.FieldError) string {
+	text := e.Kind() == reflect.String
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "max":
+		if text {
+			return fmt.Sprintf("must have at most %s characters", e.Param())
+		}
+		if e.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at most %s items", e.Param())
+		}
+		return fmt.Sprintf("must be at most %s", e.Param())
+	case "min":
+		if text {
+			return fmt.Sprintf("must have at least %s characters", e.Param())
+		}
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "len":
+		return fmt.Sprintf("must have %s characters", e.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(e.Param(), " ", ", "))
+	case "gte":
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "lte":
+		return fmt.Sprintf("must be at most %s", e.Param())
+	default:
+		return "is invalid"
+	}
+}
+
+func joinField(prefix string, field string) string {
+	if prefix == "" {
+		return field
+	}
+	if field == "" {
+		return prefix
+	}
+	return prefix + "." + field
+}
@@ -0,0 +1,32 @@
+USE userdb;
+
+CREATE TABLE categories (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE store_categories (
+    store_id INT(6) NOT NULL,
+    category_id INT(6) NOT NULL,
+    PRIMARY KEY (store_id, category_id),
+    FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+ALTER TABLE stores ADD COLUMN tags VARCHAR(700) DEFAULT '';
+
+-- Rows owned by a store or user go with it, so purging only deletes the parent.
+ALTER TABLE store_members DROP FOREIGN KEY store_members_ibfk_1;
+ALTER TABLE store_members ADD CONSTRAINT fk_store_members_store FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE;
+
+ALTER TABLE store_hours DROP FOREIGN KEY store_hours_ibfk_1;
+ALTER TABLE store_hours ADD CONSTRAINT fk_store_hours_store FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE;
+
+ALTER TABLE store_holidays DROP FOREIGN KEY store_holidays_ibfk_1;
+ALTER TABLE store_holidays ADD CONSTRAINT fk_store_holidays_store FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE;
+
+ALTER TABLE addresses DROP FOREIGN KEY addresses_ibfk_1;
+ALTER TABLE addresses ADD CONSTRAINT fk_addresses_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE phone_verifications DROP FOREIGN KEY phone_verifications_ibfk_1;
+ALTER TABLE phone_verifications ADD CONSTRAINT fk_phone_verifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,22 @@
+USE userdb;
+
+CREATE TABLE reviews (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    store_id INT(6) NOT NULL,
+    user_id INT(6) NOT NULL,
+    rating TINYINT NOT NULL,
+    text VARCHAR(2000),
+    reply VARCHAR(2000),
+    replied_at DATETIME NULL,
+    hidden BOOLEAN DEFAULT FALSE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE KEY uq_reviews (store_id, user_id),
+    FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+ALTER TABLE stores ADD COLUMN rating_avg DECIMAL(3, 2) DEFAULT 0;
+ALTER TABLE stores ADD COLUMN rating_count INT DEFAULT 0;
+
+CREATE INDEX idx_stores_rating ON stores (rating_avg, rating_count);
@@ -0,0 +1,11 @@
+USE userdb;
+
+CREATE TABLE favorites (
+    user_id INT(6) NOT NULL,
+    store_id INT(6) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, store_id),
+    INDEX idx_favorites_store (store_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE stores ADD COLUMN rejection_reason VARCHAR(500);
+ALTER TABLE stores ADD COLUMN submitted_at DATETIME NULL;
+ALTER TABLE stores ADD COLUMN reviewed_at DATETIME NULL;
+
+UPDATE stores SET status = 'approved', reviewed_at = NOW();
+
+CREATE INDEX idx_stores_status ON stores (status, submitted_at);
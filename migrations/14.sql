@@ -0,0 +1,14 @@
+USE userdb;
+
+-- The service fills the slug of the existing stores on startup.
+ALTER TABLE stores ADD COLUMN slug VARCHAR(120) NULL;
+CREATE UNIQUE INDEX idx_stores_slug ON stores (slug);
+
+CREATE TABLE store_slugs (
+    slug VARCHAR(120) NOT NULL,
+    store_id INT(6) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (slug),
+    INDEX idx_store_slugs_store (store_id),
+    FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+USE userdb;
+
+ALTER TABLE profiles ADD COLUMN avatar_path VARCHAR(100) NOT NULL DEFAULT '' AFTER state;
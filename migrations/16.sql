@@ -0,0 +1,12 @@
+USE userdb;
+
+CREATE TABLE user_preferences (
+    user_id INT(6) NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    notifications JSON NOT NULL,
+    marketing BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+USE userdb;
+
+ALTER TABLE profiles ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE stores ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN description VARCHAR(2000) NOT NULL DEFAULT '' AFTER name;
+
+CREATE FULLTEXT INDEX ft_stores_search ON stores (name, city, description, tags);
@@ -0,0 +1,14 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN created_at DATETIME NULL;
+
+UPDATE stores SET created_at = COALESCE(submitted_at, NOW());
+
+ALTER TABLE stores MODIFY created_at DATETIME NOT NULL;
+
+CREATE INDEX idx_stores_name ON stores (name, id);
+CREATE INDEX idx_stores_created ON stores (created_at, id);
+DROP INDEX idx_stores_rating ON stores;
+CREATE INDEX idx_stores_rating ON stores (rating_avg, rating_count, id);
+CREATE INDEX idx_reviews_store_updated ON reviews (store_id, updated_at, id);
+CREATE INDEX idx_favorites_user_created ON favorites (user_id, created_at, store_id);
@@ -0,0 +1,5 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN archived_at DATETIME NULL;
+
+CREATE INDEX idx_stores_archived_at ON stores (archived_at);
@@ -0,0 +1,22 @@
+USE userdb;
+
+ALTER TABLE users ADD COLUMN created_at DATETIME NULL;
+
+-- Users from before this column get the date of their first store, review or
+-- follow. Those without any are left NULL and out of created date searches.
+UPDATE users u
+JOIN (
+    SELECT user_id, MIN(created_at) AS created_at FROM (
+        SELECT user_id, created_at FROM stores
+        UNION ALL
+        SELECT user_id, created_at FROM reviews
+        UNION ALL
+        SELECT user_id, created_at FROM favorites
+    ) activity
+    GROUP BY user_id
+) a ON a.user_id = u.id
+SET u.created_at = a.created_at;
+
+CREATE INDEX idx_users_created ON users (created_at, id);
+CREATE INDEX idx_profiles_user ON profiles (user_id);
+CREATE INDEX idx_store_members_user ON store_members (user_id, role);
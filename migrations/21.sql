@@ -0,0 +1,10 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN search_text VARCHAR(3000) NOT NULL DEFAULT '';
+
+-- Lowercased here so the index has content when it's rebuilt, the service
+-- folds the accents on startup, see RefreshSearchText.
+UPDATE stores SET search_text = LOWER(CONCAT_WS(' ', name, city, description, REPLACE(tags, ',', ' ')));
+
+DROP INDEX ft_stores_search ON stores;
+CREATE FULLTEXT INDEX ft_stores_search ON stores (search_text);
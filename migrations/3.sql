@@ -0,0 +1,14 @@
+USE userdb;
+
+CREATE TABLE store_members (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    store_id INT(6) NOT NULL,
+    user_id INT(6) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    UNIQUE KEY uq_store_members (store_id, user_id),
+    FOREIGN KEY (store_id) REFERENCES stores(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+INSERT INTO store_members (store_id, user_id, role)
+SELECT id, user_id, 'owner' FROM stores WHERE user_id IS NOT NULL;
@@ -0,0 +1,20 @@
+USE userdb;
+
+CREATE TABLE addresses (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    user_id INT(6) NOT NULL,
+    label VARCHAR(50),
+    address VARCHAR(100),
+    block VARCHAR(100),
+    zip_code VARCHAR(100),
+    city VARCHAR(100),
+    state VARCHAR(100),
+    default_shipping BOOLEAN DEFAULT FALSE,
+    default_billing BOOLEAN DEFAULT FALSE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+INSERT INTO addresses (user_id, label, address, block, zip_code, city, state, default_shipping, default_billing)
+SELECT user_id, 'home', address, block, zip_code, city, state, TRUE, TRUE
+FROM profiles
+WHERE user_id IS NOT NULL AND COALESCE(address, '') <> '';
@@ -0,0 +1,3 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN zip_code VARCHAR(100) AFTER block;
@@ -0,0 +1,8 @@
+USE userdb;
+
+ALTER TABLE profiles ADD COLUMN cpf VARCHAR(11) NULL AFTER name;
+ALTER TABLE profiles ADD CONSTRAINT uq_profiles_cpf UNIQUE (cpf);
+
+ALTER TABLE stores ADD COLUMN cnpj VARCHAR(14) NULL AFTER name;
+ALTER TABLE stores ADD COLUMN cpf VARCHAR(11) NULL AFTER cnpj;
+ALTER TABLE stores ADD CONSTRAINT uq_stores_cnpj UNIQUE (cnpj);
@@ -0,0 +1,20 @@
+USE userdb;
+
+ALTER TABLE profiles ADD COLUMN phone VARCHAR(16) NULL AFTER cpf;
+ALTER TABLE profiles ADD COLUMN phone_verified BOOLEAN DEFAULT FALSE AFTER phone;
+
+ALTER TABLE stores ADD COLUMN phone VARCHAR(16) NULL AFTER cpf;
+ALTER TABLE stores ADD COLUMN phone_verified BOOLEAN DEFAULT FALSE AFTER phone;
+
+CREATE TABLE phone_verifications (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    user_id INT(6) NOT NULL,
+    phone VARCHAR(16) NOT NULL,
+    code VARCHAR(200) NOT NULL,
+    attempts INT DEFAULT 0,
+    sends INT NOT NULL DEFAULT 1,
+    sent_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    UNIQUE KEY uq_phone_verifications (user_id, phone),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
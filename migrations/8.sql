@@ -0,0 +1,25 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN timezone VARCHAR(50) DEFAULT 'America/Sao_Paulo';
+ALTER TABLE stores ADD COLUMN vacation BOOLEAN DEFAULT FALSE;
+ALTER TABLE stores ADD COLUMN reopen_at DATETIME NULL;
+
+CREATE TABLE store_hours (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    store_id INT(6) NOT NULL,
+    weekday TINYINT NOT NULL,
+    opens CHAR(5) NOT NULL,
+    closes CHAR(5) NOT NULL,
+    FOREIGN KEY (store_id) REFERENCES stores(id)
+);
+
+CREATE TABLE store_holidays (
+    id INT(6) AUTO_INCREMENT PRIMARY KEY,
+    store_id INT(6) NOT NULL,
+    date CHAR(10) NOT NULL,
+    closed BOOLEAN DEFAULT TRUE,
+    opens CHAR(5),
+    closes CHAR(5),
+    note VARCHAR(100),
+    FOREIGN KEY (store_id) REFERENCES stores(id)
+);
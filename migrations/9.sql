@@ -0,0 +1,6 @@
+USE userdb;
+
+ALTER TABLE stores ADD COLUMN latitude DECIMAL(9, 6) NULL;
+ALTER TABLE stores ADD COLUMN longitude DECIMAL(9, 6) NULL;
+
+CREATE INDEX idx_stores_location ON stores (latitude, longitude);
@@ -0,0 +1,124 @@
+package normalize
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidCEP is returned when a CEP doesn't have 8 digits.
+	ErrInvalidCEP = errors.New("invalid CEP")
+	// ErrInvalidState is returned when a state isn't a brazilian UF.
+	ErrInvalidState = errors.New("invalid state")
+)
+
+// states maps the folded state names to their UF codes.
+var states = map[string]string{
+	"acre":                "AC",
+	"alagoas":             "AL",
+	"amapa":               "AP",
+	"amazonas":            "AM",
+	"bahia":               "BA",
+	"ceara":               "CE",
+	"distrito federal":    "DF",
+	"espirito santo":      "ES",
+	"goias":               "GO",
+	"maranhao":            "MA",
+	"mato grosso":         "MT",
+	"mato grosso do sul":  "MS",
+	"minas gerais":        "MG",
+	"para":                "PA",
+	"paraiba":             "PB",
+	"parana":              "PR",
+	"pernambuco":          "PE",
+	"piaui":               "PI",
+	"rio de janeiro":      "RJ",
+	"rio grande do norte": "RN",
+	"rio grande do sul":   "RS",
+	"rondonia":            "RO",
+	"roraima":             "RR",
+	"santa catarina":      "SC",
+	"sao paulo":           "SP",
+	"sergipe":             "SE",
+	"tocantins":           "TO",
+}
+
+// lowerWords are kept in lower case inside city names.
+var lowerWords = map[string]bool{
+	"d":   true,
+	"da":  true,
+	"das": true,
+	"de":  true,
+	"do":  true,
+	"dos": true,
+	"e":   true,
+}
+
+// CEP returns the 8 digits of a CEP, `01310-100` becomes `01310100`.
+func CEP(cep string) (string, error) {
+	digits := Digits(cep)
+	if len(digits) != 8 {
+		return "", ErrInvalidCEP
+	}
+	return digits, nil
+}
+
+// UF returns the UF code of a state given by its code or name.
+func UF(state string) (string, error) {
+	state = strings.TrimSpace(state)
+	if len(state) == 2 {
+		code := strings.ToUpper(state)
+		for _, uf := range states {
+			if uf == code {
+				return code, nil
+			}
+		}
+	}
+
+	uf, ok := states[Fold(state)]
+	if !ok {
+		return "", ErrInvalidState
+	}
+	return uf, nil
+}
+
+// City returns a city name with collapsed spaces and capitalized words,
+// `  SÃO  joão DEL-REI` becomes `São João Del-Rei`.
+func City(city string) string {
+	words := strings.Fields(strings.ToLower(city))
+	for i, word := range words {
+		if i > 0 && lowerWords[word] {
+			continue
+		}
+		if i > 0 && strings.HasPrefix(word, "d'") {
+			words[i] = "d'" + capitalize(word[2:])
+			continue
+		}
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// Digits strips everything but the digits of a text.
+func Digits(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func capitalize(word string) string {
+	runes := []rune(word)
+	upper := true
+	for i, r := range runes {
+		if upper {
+			runes[i] = unicode.ToUpper(r)
+		}
+		upper = r == '-' || r == '\''
+	}
+	return string(runes)
+}
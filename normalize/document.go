@@ -0,0 +1,90 @@
+package normalize
+
+import "errors"
+
+var (
+	// ErrInvalidCPF is returned when a CPF has a wrong length or check digits.
+	ErrInvalidCPF = errors.New("invalid CPF")
+	// ErrInvalidCNPJ is returned when a CNPJ has a wrong length or check digits.
+	ErrInvalidCNPJ = errors.New("invalid CNPJ")
+)
+
+// CPF returns the 11 digits of a valid CPF, `123.456.789-09` becomes `12345678909`.
+func CPF(cpf string) (string, error) {
+	digits := Digits(cpf)
+	if len(digits) != 11 || repeated(digits) {
+		return "", ErrInvalidCPF
+	}
+	if checkDigit(digits[:9], 10) != digits[9] || checkDigit(digits[:10], 11) != digits[10] {
+		return "", ErrInvalidCPF
+	}
+	return digits, nil
+}
+
+// CNPJ returns the 14 digits of a valid CNPJ, `11.222.333/0001-81` becomes `11222333000181`.
+func CNPJ(cnpj string) (string, error) {
+	digits := Digits(cnpj)
+	if len(digits) != 14 || repeated(digits) {
+		return "", ErrInvalidCNPJ
+	}
+	if cnpjCheckDigit(digits[:12]) != digits[12] || cnpjCheckDigit(digits[:13]) != digits[13] {
+		return "", ErrInvalidCNPJ
+	}
+	return digits, nil
+}
+
+// MaskCPF hides the first and check digits of a CPF, `12345678909` becomes `***.456.789-**`.
+func MaskCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return "***." + cpf[3:6] + "." + cpf[6:9] + "-**"
+}
+
+// MaskCNPJ hides the first and check digits of a CNPJ, `11222333000181` becomes `**.222.333/0001-**`.
+func MaskCNPJ(cnpj string) string {
+	if len(cnpj) != 14 {
+		return cnpj
+	}
+	return "**." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-**"
+}
+
+// checkDigit computes a CPF check digit with weights going down from weight to 2.
+func checkDigit(digits string, weight int) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i]-'0') * (weight - i)
+	}
+	return modDigit(sum)
+}
+
+// cnpjCheckDigit computes a CNPJ check digit with weights cycling from 9 down to 2.
+func cnpjCheckDigit(digits string) byte {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	return modDigit(sum)
+}
+
+func modDigit(sum int) byte {
+	rest := sum % 11
+	if rest < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - rest)
+}
+
+func repeated(digits string) bool {
+	for i := 1; i < len(digits); i++ {
+		if digits[i] != digits[0] {
+			return false
+		}
+	}
+	return true
+}
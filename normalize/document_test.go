@@ -0,0 +1,81 @@
+package normalize
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCPF(t *testing.T) {
+	tests := []struct {
+		cpf     string
+		want    string
+		wantErr error
+	}{
+		{"123.456.789-09", "12345678909", nil},
+		{"529.982.247-25", "52998224725", nil},
+		{"52998224725", "52998224725", nil},
+		{" 529 982 247 25 ", "52998224725", nil},
+		{"529.982.247-24", "", ErrInvalidCPF},
+		{"529.982.247-15", "", ErrInvalidCPF},
+		{"111.111.111-11", "", ErrInvalidCPF},
+		{"000.000.000-00", "", ErrInvalidCPF},
+		{"5299822472", "", ErrInvalidCPF},
+		{"529982247250", "", ErrInvalidCPF},
+		{"", "", ErrInvalidCPF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cpf, func(t *testing.T) {
+			got, err := CPF(tt.cpf)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("CPF(%q) = %q, %v, want %q, %v", tt.cpf, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCNPJ(t *testing.T) {
+	tests := []struct {
+		cnpj    string
+		want    string
+		wantErr error
+	}{
+		{"11.222.333/0001-81", "11222333000181", nil},
+		{"11222333000181", "11222333000181", nil},
+		{"45.723.174/0001-10", "45723174000110", nil},
+		{"11.222.333/0001-82", "", ErrInvalidCNPJ},
+		{"11.222.333/0001-91", "", ErrInvalidCNPJ},
+		{"11.111.111/1111-11", "", ErrInvalidCNPJ},
+		{"1122233300018", "", ErrInvalidCNPJ},
+		{"123.456.789-09", "", ErrInvalidCNPJ},
+		{"", "", ErrInvalidCNPJ},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cnpj, func(t *testing.T) {
+			got, err := CNPJ(tt.cnpj)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("CNPJ(%q) = %q, %v, want %q, %v", tt.cnpj, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaskDocuments(t *testing.T) {
+	tests := []struct {
+		name string
+		mask func(string) string
+		doc  string
+		want string
+	}{
+		{"CPF", MaskCPF, "12345678909", "***.456.789-**"},
+		{"CPF with a wrong length", MaskCPF, "1234567890", "1234567890"},
+		{"CNPJ", MaskCNPJ, "11222333000181", "**.222.333/0001-**"},
+		{"CNPJ with a wrong length", MaskCNPJ, "1122233300018", "1122233300018"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mask(tt.doc); got != tt.want {
+				t.Errorf("mask(%q) = %q, want %q", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold strips diacritics, folds case and collapses spaces, `São  Paulo` becomes `sao paulo`.
+func Fold(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		result = text
+	}
+	return strings.Join(strings.Fields(strings.ToLower(result)), " ")
+}
@@ -0,0 +1,47 @@
+package normalize
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"São Paulo", "sao paulo"},
+		{"Brechó", "brecho"},
+		{"Açaí", "acai"},
+		{"Maçã", "maca"},
+		{"Pão", "pao"},
+		{"Limões", "limoes"},
+		{"BRECHÓ DA ESQUINA", "brecho da esquina"},
+		{"  São   João  ", "sao joao"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Fold(tt.text); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchText(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []string
+		want  string
+	}{
+		{"name and city", []string{"Brechó", "São Paulo"}, "brecho sao paulo"},
+		{"cedilla and tilde", []string{"Moda Ação", "Florianópolis", "Peças únicas"}, "moda acao florianopolis pecas unicas"},
+		{"uppercase", []string{"BAZAR", "CURITIBA"}, "bazar curitiba"},
+		{"empty parts", []string{"Brechó", "", "vintage"}, "brecho vintage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchText(tt.parts...); got != tt.want {
+				t.Errorf("SearchText(%q) = %q, want %q", tt.parts, got, tt.want)
+			}
+		})
+	}
+}
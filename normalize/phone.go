@@ -0,0 +1,27 @@
+package normalize
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone is returned when a phone number can't be written in E.164.
+var ErrInvalidPhone = errors.New("invalid phone")
+
+// Phone returns a phone number in E.164, numbers without a country code are
+// taken as brazilian, `(11) 99999-8888` becomes `+5511999998888`.
+func Phone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	digits := Digits(phone)
+	if !strings.HasPrefix(phone, "+") {
+		if len(digits) != 10 && len(digits) != 11 {
+			return "", ErrInvalidPhone
+		}
+		digits = "55" + digits
+	}
+
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	return "+" + digits, nil
+}
@@ -0,0 +1,35 @@
+package normalize
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPhone(t *testing.T) {
+	tests := []struct {
+		phone   string
+		want    string
+		wantErr error
+	}{
+		{"(11) 99999-8888", "+5511999998888", nil},
+		{"11 3333-4444", "+551133334444", nil},
+		{"11999998888", "+5511999998888", nil},
+		{"+55 11 99999-8888", "+5511999998888", nil},
+		{"+1 (415) 555-2671", "+14155552671", nil},
+		{"  +44 20 7946 0958 ", "+442079460958", nil},
+		{"99999-8888", "", ErrInvalidPhone},
+		{"011999998888", "", ErrInvalidPhone},
+		{"+0 11 99999-8888", "", ErrInvalidPhone},
+		{"+1234567", "", ErrInvalidPhone},
+		{"+1234567890123456", "", ErrInvalidPhone},
+		{"", "", ErrInvalidPhone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			got, err := Phone(tt.phone)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Phone(%q) = %q, %v, want %q, %v", tt.phone, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
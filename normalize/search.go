@@ -0,0 +1,9 @@
+package normalize
+
+import "strings"
+
+// SearchText folds the searchable parts of a record into a single text,
+// `Brechó`, `São Paulo` becomes `brecho sao paulo`.
+func SearchText(parts ...string) string {
+	return Fold(strings.Join(parts, " "))
+}
@@ -0,0 +1,21 @@
+package normalize
+
+import "strings"
+
+// Slug turns a name into an URL friendly slug, `Café  do João!` becomes `cafe-do-joao`.
+func Slug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range Fold(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
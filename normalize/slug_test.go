@@ -0,0 +1,26 @@
+package normalize
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Café  do João!", "cafe-do-joao"},
+		{"Brechó da Ana", "brecho-da-ana"},
+		{"  Loja 24 Horas  ", "loja-24-horas"},
+		{"AÇAÍ & PÃO", "acai-pao"},
+		{"---moda---", "moda"},
+		{"loja_da_ana", "loja-da-ana"},
+		{"!!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Slug(tt.text); got != tt.want {
+				t.Errorf("Slug(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
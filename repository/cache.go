@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/restore/user/cache"
+	"github.com/restore/user/entity"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// storesGeneration names the key whose value is part of every store and
+// search key. Store writes replace it, which invalidates them all, store
+// writes being rare next to reads. Only instances sharing the cache, like on
+// Redis, see the new generation. With the in-process LRU the other replicas
+// keep serving their entries until the TTL expires.
+const storesGeneration = "stores:generation"
+
+var generationSeq atomic.Uint64
+
+// cacheStats counts the hits and misses of each lookup, and the cache errors.
+var cacheStats = expvar.NewMap("cache")
+
+// CachedUser reads stores, store searches, users and profiles through a cache and
+// invalidates them on the writes changing them. Cache errors fall back to the
+// database. Passwords are never cached, GetUserCredentials reads them.
+type CachedUser struct {
+	*User
+	cache cache.Cache
+	cfg   *cache.Config
+}
+
+type cachedSearch struct {
+	Stores []entity.Store
+	Next   string
+}
+
+func NewCachedUser(r *User, c cache.Cache, cfg *cache.Config) *CachedUser {
+	return &CachedUser{
+		User:  r,
+		cache: c,
+		cfg:   cfg,
+	}
+}
+
+func (c *CachedUser) GetStoreByID(ctx context.Context, id int) (*entity.Store, error) {
+	key := fmt.Sprintf("store:%s:%d", c.generation(), id)
+	var result entity.Store
+	if c.load("store", key, &result) {
+		return &result, nil
+	}
+
+	store, err := c.User.GetStoreByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	store.User.Password = ""
+	c.save(key, store, c.cfg.StoreTTL)
+	return store, nil
+}
+
+func (c *CachedUser) SearchStore(ctx context.Context, filter *entity.StoreFilter) ([]entity.Store, string, error) {
+	key := "search:" + c.generation() + ":" + filterKey(filter)
+	var result cachedSearch
+	if c.load("search", key, &result) {
+		return result.Stores, result.Next, nil
+	}
+
+	stores, next, err := c.User.SearchStore(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	c.save(key, cachedSearch{Stores: stores, Next: next}, c.cfg.SearchTTL)
+	return stores, next, nil
+}
+
+func (c *CachedUser) StoreFacets(ctx context.Context, filter *entity.StoreFilter) (*entity.StoreFacets, error) {
+	unpaged := *filter
+	unpaged.Sort = ""
+	unpaged.Page = entity.PageRequest{}
+	key := "facets:" + c.generation() + ":" + filterKey(&unpaged)
+	var result entity.StoreFacets
+	if c.load("facets", key, &result) {
+		return &result, nil
+	}
+
+	facets, err := c.User.StoreFacets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	c.save(key, facets, c.cfg.SearchTTL)
+	return facets, nil
+}
+
+func (c *CachedUser) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	key := "user:" + email
+	var result entity.User
+	if c.load("user", key, &result) {
+		return &result, nil
+	}
+
+	user, err := c.User.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	c.save(key, user, c.cfg.UserTTL)
+	return user, nil
+}
+
+func (c *CachedUser) GetUserProfile(ctx context.Context, email string) (*entity.Profile, error) {
+	key := "profile:" + email
+	var result entity.Profile
+	if c.load("profile", key, &result) {
+		return &result, nil
+	}
+
+	profile, err := c.User.GetUserProfile(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	profile.User.Password = ""
+	c.save(key, profile, c.cfg.UserTTL)
+	return profile, nil
+}
+
+func (c *CachedUser) UpdateProfile(ctx context.Context, id int, version int, profile *entity.Profile) error {
+	err := c.User.UpdateProfile(ctx, id, version, profile)
+	if err == nil {
+		c.forgetProfile(ctx, id)
+	}
+	return err
+}
+
+func (c *CachedUser) UpdateProfileAvatar(ctx context.Context, id int, avatar string) error {
+	err := c.User.UpdateProfileAvatar(ctx, id, avatar)
+	if err == nil {
+		c.forgetProfile(ctx, id)
+	}
+	return err
+}
+
+func (c *CachedUser) CreateStore(ctx context.Context, store *entity.Store) error {
+	return c.invalidateStores(c.User.CreateStore(ctx, store))
+}
+
+func (c *CachedUser) UpdateStore(ctx context.Context, id int, version int, store *entity.Store) error {
+	return c.invalidateStores(c.User.UpdateStore(ctx, id, version, store))
+}
+
+func (c *CachedUser) ArchiveStore(ctx context.Context, id int) error {
+	return c.invalidateStores(c.User.ArchiveStore(ctx, id))
+}
+
+func (c *CachedUser) RestoreStore(ctx context.Context, id int, since time.Time) error {
+	return c.invalidateStores(c.User.RestoreStore(ctx, id, since))
+}
+
+func (c *CachedUser) PurgeStore(ctx context.Context, store *entity.Store, cleanup func(userDeleted bool) error) (bool, error) {
+	deleted, err := c.User.PurgeStore(ctx, store, cleanup)
+	if err == nil && deleted {
+		c.forget("user:" + store.User.Email)
+	}
+	return deleted, c.invalidateStores(err)
+}
+
+func (c *CachedUser) UpdateSchedule(ctx context.Context, id int, version int, schedule *entity.StoreSchedule) error {
+	return c.invalidateStores(c.User.UpdateSchedule(ctx, id, version, schedule))
+}
+
+func (c *CachedUser) UpdateStoreStatus(ctx context.Context, store *entity.Store) error {
+	return c.invalidateStores(c.User.UpdateStoreStatus(ctx, store))
+}
+
+func (c *CachedUser) UpdateStoreSlug(ctx context.Context, id int, slug string) error {
+	return c.invalidateStores(c.User.UpdateStoreSlug(ctx, id, slug))
+}
+
+func (c *CachedUser) ConfirmPhone(ctx context.Context, userID int, phone string) error {
+	err := c.User.ConfirmPhone(ctx, userID, phone)
+	if err == nil {
+		c.forgetUserProfile(ctx, userID)
+	}
+	return c.invalidateStores(err)
+}
+
+func (c *CachedUser) SaveReview(ctx context.Context, review *entity.Review) (bool, error) {
+	created, err := c.User.SaveReview(ctx, review)
+	return created, c.invalidateStores(err)
+}
+
+func (c *CachedUser) HideReview(ctx context.Context, review *entity.Review, hidden bool) error {
+	return c.invalidateStores(c.User.HideReview(ctx, review, hidden))
+}
+
+func (c *CachedUser) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	return c.invalidateStores(c.User.UpdateCategory(ctx, category))
+}
+
+func (c *CachedUser) DeleteCategory(ctx context.Context, id int) error {
+	return c.invalidateStores(c.User.DeleteCategory(ctx, id))
+}
+
+func (c *CachedUser) RefreshSearchText(ctx context.Context) (int, error) {
+	updated, err := c.User.RefreshSearchText(ctx)
+	if updated == 0 {
+		return updated, err
+	}
+	return updated, c.invalidateStores(err)
+}
+
+// generation reads the current stores generation, starting a new one when
+// it's missing so entries of an evicted generation are never read again.
+func (c *CachedUser) generation() string {
+	value, ok, err := c.cache.Get(storesGeneration)
+	if err != nil {
+		cacheStats.Add("errors", 1)
+	}
+	if ok {
+		return string(value)
+	}
+
+	generation := newGeneration()
+	if err == nil {
+		err = c.cache.Set(storesGeneration, []byte(generation), 0)
+		if err != nil {
+			cacheStats.Add("errors", 1)
+		}
+	}
+	return generation
+}
+
+// invalidateStores starts a new stores generation after a successful write,
+// the write error is passed through.
+func (c *CachedUser) invalidateStores(err error) error {
+	if err != nil {
+		return err
+	}
+	generation := newGeneration()
+	if c.cache.Set(storesGeneration, []byte(generation), 0) != nil {
+		cacheStats.Add("errors", 1)
+	}
+	return nil
+}
+
+// forgetProfile drops the cached profile, which is keyed by the user email.
+func (c *CachedUser) forgetProfile(ctx context.Context, profileID int) {
+	profile, err := c.User.GetProfileByID(ctx, profileID)
+	if err != nil {
+		cacheStats.Add("errors", 1)
+		return
+	}
+	c.forgetUserProfile(ctx, profile.UserID)
+}
+
+func (c *CachedUser) forgetUserProfile(ctx context.Context, userID int) {
+	user, err := c.User.GetUserByID(ctx, userID)
+	if err != nil {
+		cacheStats.Add("errors", 1)
+		return
+	}
+	c.forget("profile:" + user.Email)
+}
+
+// newGeneration names a stores generation, the sequence keeps two writes in
+// the same clock tick from reusing a name.
+func newGeneration() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + "." + strconv.FormatUint(generationSeq.Add(1), 36)
+}
+
+func (c *CachedUser) forget(key string) {
+	if c.cache.Delete(key) != nil {
+		cacheStats.Add("errors", 1)
+	}
+}
+
+// load decodes a cached value into v, reporting whether it was there.
+func (c *CachedUser) load(kind string, key string, v interface{}) bool {
+	value, ok, err := c.cache.Get(key)
+	if err == nil && ok {
+		err = gob.NewDecoder(bytes.NewReader(value)).Decode(v)
+	}
+	if err != nil {
+		cacheStats.Add("errors", 1)
+		ok = false
+	}
+	if !ok {
+		cacheStats.Add(kind+"_misses", 1)
+		return false
+	}
+	cacheStats.Add(kind+"_hits", 1)
+	return true
+}
+
+func (c *CachedUser) save(key string, v interface{}, ttl time.Duration) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	if err == nil {
+		err = c.cache.Set(key, buf.Bytes(), ttl)
+	}
+	if err != nil {
+		cacheStats.Add("errors", 1)
+	}
+}
+
+// filterKey hashes a search filter into a short key.
+func filterKey(filter *entity.StoreFilter) string {
+	text, _ := json.Marshal(filter)
+	sum := sha256.Sum256(text)
+	return hex.EncodeToString(sum[:16])
+}
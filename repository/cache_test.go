@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/restore/user/cache"
+	"github.com/restore/user/entity"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDriver answers every query with one row holding the columns the store
+// writes read, and every statement as affecting one row, so the repository
+// methods succeed without a database.
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeRows struct {
+	columns []string
+	values  []driver.Value
+	done    bool
+}
+
+var fakeRow = map[string]driver.Value{
+	"id":          int64(1),
+	"version":     int64(1),
+	"user_id":     int64(1),
+	"store_id":    int64(1),
+	"category_id": int64(1),
+	"status":      entity.StatusApproved,
+	"email":       "owner@restore.com",
+	"password":    "$2a$10$hash",
+	"name":        "Brechó",
+	"slug":        "brecho",
+	"phone":       "+5511999999999",
+}
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return fakeConn{}, nil
+}
+
+func (fakeConn) Commit() error {
+	return nil
+}
+
+func (fakeConn) Rollback() error {
+	return nil
+}
+
+func (fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return fakeResult{}, nil
+}
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) {
+	return 1, nil
+}
+
+func (fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.HasPrefix(query, "SELECT count(") {
+		return &fakeRows{columns: []string{"count"}, values: []driver.Value{int64(0)}}, nil
+	}
+	rows := &fakeRows{}
+	for column, value := range fakeRow {
+		rows.columns = append(rows.columns, column)
+		rows.values = append(rows.values, value)
+	}
+	return rows, nil
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func newTestCachedUser(t *testing.T) *CachedUser {
+	t.Helper()
+
+	conn, err := sql.Open("fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &cache.Config{StoreTTL: time.Minute, SearchTTL: time.Minute, UserTTL: time.Minute}
+	return NewCachedUser(NewUser(db), cache.NewLRU(100, 0), cfg)
+}
+
+func TestCachedUserInvalidatesStores(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		write func(c *CachedUser) error
+	}{
+		{"CreateStore", func(c *CachedUser) error {
+			return c.CreateStore(ctx, &entity.Store{Name: "Brechó", UserID: 1})
+		}},
+		{"UpdateStore", func(c *CachedUser) error {
+			return c.UpdateStore(ctx, 1, 1, &entity.Store{Name: "Brechó da Ana"})
+		}},
+		{"ArchiveStore", func(c *CachedUser) error {
+			return c.ArchiveStore(ctx, 1)
+		}},
+		{"RestoreStore", func(c *CachedUser) error {
+			return c.RestoreStore(ctx, 1, time.Now().Add(-time.Hour))
+		}},
+		{"PurgeStore", func(c *CachedUser) error {
+			_, err := c.PurgeStore(ctx, &entity.Store{ID: 1, UserID: 1}, func(bool) error { return nil })
+			return err
+		}},
+		{"UpdateSchedule", func(c *CachedUser) error {
+			return c.UpdateSchedule(ctx, 1, 0, &entity.StoreSchedule{Timezone: "America/Sao_Paulo"})
+		}},
+		{"UpdateStoreStatus", func(c *CachedUser) error {
+			return c.UpdateStoreStatus(ctx, &entity.Store{ID: 1, Status: entity.StatusApproved})
+		}},
+		{"UpdateStoreSlug", func(c *CachedUser) error {
+			return c.UpdateStoreSlug(ctx, 1, "brecho")
+		}},
+		{"ConfirmPhone", func(c *CachedUser) error {
+			return c.ConfirmPhone(ctx, 1, "+5511999999999")
+		}},
+		{"SaveReview", func(c *CachedUser) error {
+			_, err := c.SaveReview(ctx, &entity.Review{StoreID: 1, UserID: 2, Rating: 5})
+			return err
+		}},
+		{"HideReview", func(c *CachedUser) error {
+			return c.HideReview(ctx, &entity.Review{ID: 1, StoreID: 1}, true)
+		}},
+		{"UpdateCategory", func(c *CachedUser) error {
+			return c.UpdateCategory(ctx, &entity.Category{ID: 1, Name: "Roupas"})
+		}},
+		{"DeleteCategory", func(c *CachedUser) error {
+			return c.DeleteCategory(ctx, 1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCachedUser(t)
+			_, err := c.GetStoreByID(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			generation := c.generation()
+			key := "store:" + generation + ":1"
+			if _, ok, _ := c.cache.Get(key); !ok {
+				t.Fatalf("store wasn't cached under %q", key)
+			}
+
+			err = tt.write(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.generation() == generation {
+				t.Errorf("%s kept the stores generation", tt.name)
+			}
+		})
+	}
+}
+
+func TestCachedUserForgetsProfiles(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		write func(c *CachedUser) error
+	}{
+		{"UpdateProfile", func(c *CachedUser) error {
+			return c.UpdateProfile(ctx, 1, 1, &entity.Profile{Name: "Ana"})
+		}},
+		{"UpdateProfileAvatar", func(c *CachedUser) error {
+			return c.UpdateProfileAvatar(ctx, 1, "avatar.webp")
+		}},
+		{"ConfirmPhone", func(c *CachedUser) error {
+			return c.ConfirmPhone(ctx, 1, "+5511999999999")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCachedUser(t)
+			_, err := c.GetUserProfile(ctx, "owner@restore.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok, _ := c.cache.Get("profile:owner@restore.com"); !ok {
+				t.Fatal("profile wasn't cached")
+			}
+
+			err = tt.write(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok, _ := c.cache.Get("profile:owner@restore.com"); ok {
+				t.Errorf("%s kept the cached profile", tt.name)
+			}
+		})
+	}
+}
+
+func TestCachedUserDropsPasswords(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCachedUser(t)
+
+	user, err := c.GetUserByEmail(ctx, "owner@restore.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := c.GetStoreByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Password != "" || store.User.Password != "" {
+		t.Errorf("password returned from a cached lookup")
+	}
+
+	var cached entity.User
+	if !c.load("user", "user:owner@restore.com", &cached) || cached.Password != "" {
+		t.Errorf("password kept in the cached user")
+	}
+
+	credentials, err := c.GetUserCredentials(ctx, "owner@restore.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.Password == "" {
+		t.Errorf("GetUserCredentials didn't read the password")
+	}
+}
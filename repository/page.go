@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/restore/user/entity"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	// MaxPageSize bounds every listing, larger limits are lowered to it.
+	MaxPageSize = 100
+)
+
+// cursor holds the sort keys of the last row of a page, the next page starts
+// right after it. Orders without a stable key, like relevance, use Offset.
+type cursor struct {
+	Sort   string    `json:"s,omitempty"`
+	ID     int       `json:"i,omitempty"`
+	Name   string    `json:"n,omitempty"`
+	Time   time.Time `json:"t,omitempty"`
+	Rating float64   `json:"r,omitempty"`
+	Count  int       `json:"c,omitempty"`
+	Offset int       `json:"o,omitempty"`
+}
+
+// pageSize applies the default and maximum page sizes to a limit.
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
+
+// decodeCursor reads the cursor of a page request, nil means the first page.
+func decodeCursor(text string, sort string) (*cursor, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+	var result cursor
+	err = json.Unmarshal(data, &result)
+	if err != nil || result.Sort != sort {
+		return nil, entity.ErrInvalidCursor
+	}
+	return &result, nil
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
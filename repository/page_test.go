@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"github.com/restore/user/entity"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor cursor
+	}{
+		{"name", cursor{Sort: entity.SortName, ID: 7, Name: "Brechó da Ana"}},
+		{"created at", cursor{Sort: entity.SortCreatedAt, ID: 3, Time: time.Date(2024, 6, 3, 10, 30, 0, 123456789, time.UTC)}},
+		{"rating", cursor{Sort: entity.SortRating, ID: 9, Rating: 4.5, Count: 12}},
+		{"relevance", cursor{Sort: entity.SortRelevance, Offset: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.cursor.encode(), tt.cursor.Sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Time.Equal(tt.cursor.Time) {
+				t.Errorf("decoded time = %v, want %v", got.Time, tt.cursor.Time)
+			}
+			got.Time = tt.cursor.Time
+			if !reflect.DeepEqual(*got, tt.cursor) {
+				t.Errorf("decodeCursor(encode(%+v)) = %+v", tt.cursor, *got)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	name := cursor{Sort: entity.SortName, ID: 7, Name: "Ana"}.encode()
+	tests := []struct {
+		name    string
+		text    string
+		sort    string
+		want    *cursor
+		wantErr error
+	}{
+		{"first page", "", entity.SortName, nil, nil},
+		{"first page of any sort", "", entity.SortRating, nil, nil},
+		{"same sort", name, entity.SortName, &cursor{Sort: entity.SortName, ID: 7, Name: "Ana"}, nil},
+		{"other sort", name, entity.SortRating, nil, entity.ErrInvalidCursor},
+		{"not base64", "not a cursor!", entity.SortName, nil, entity.ErrInvalidCursor},
+		{"padded base64", name + "==", entity.SortName, nil, entity.ErrInvalidCursor},
+		{"not JSON", "bm90IGpzb24", entity.SortName, nil, entity.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.text, tt.sort)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decodeCursor(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCursor(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{-1, defaultPageSize},
+		{0, defaultPageSize},
+		{1, 1},
+		{MaxPageSize, MaxPageSize},
+		{MaxPageSize + 1, MaxPageSize},
+	}
+	for _, tt := range tests {
+		if got := pageSize(tt.limit); got != tt.want {
+			t.Errorf("pageSize(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -25,5 +25,7 @@ func Init(cfg *Config) (*gorm.DB, error) {
 		cfg.Port,
 		cfg.Database,
 	)
-	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	return gorm.Open(mysql.Open(dsn), &gorm.Config{
+		TranslateError: true,
+	})
 }
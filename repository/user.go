@@ -3,8 +3,22 @@ package repository
 import (
 	"context"
 	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
+	"time"
+)
+
+// profileColumns and storeColumns are written by the profile and store
+// updates, the status, rating, phone verification and avatar columns have
+// their own writers and are left as they are.
+var (
+	profileColumns = []string{"name", "cpf", "phone", "address", "block", "zip_code", "city", "state", "version"}
+	storeColumns   = []string{
+		"slug", "name", "description", "cnpj", "cpf", "phone", "address", "block", "zip_code", "city", "state",
+		"photo_path", "latitude", "longitude", "tags", "search_text", "version",
+	}
 )
 
 type User struct {
@@ -18,6 +32,10 @@ func NewUser(db *gorm.DB) *User {
 }
 
 func (u *User) CreateUser(ctx context.Context, user *entity.User) (int, error) {
+	if user.CreatedAt == nil {
+		now := time.Now()
+		user.CreatedAt = &now
+	}
 	result := u.db.Create(user)
 	if result.Error != nil {
 		return 0, result.Error
@@ -29,8 +47,21 @@ func (u *User) CreateProfile(ctx context.Context, profile *entity.Profile) error
 	return u.db.Create(profile).Error
 }
 
+// CreateStore creates the store and makes its user the owner.
 func (u *User) CreateStore(ctx context.Context, store *entity.Store) error {
-	return u.db.Create(store).Error
+	store.SearchText = storeSearchText(store)
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Omit("Categories.*").Create(store)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		return tx.Omit(clause.Associations).Create(&entity.StoreMember{
+			StoreID: store.ID,
+			UserID:  store.UserID,
+			Role:    entity.RoleOwner,
+		}).Error
+	})
 }
 
 func (u *User) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
@@ -42,6 +73,21 @@ func (u *User) GetUserByEmail(ctx context.Context, email string) (*entity.User,
 	return &result, nil
 }
 
+// GetUserCredentials gets an user with its password hash, always from the
+// database so the hash is never cached.
+func (u *User) GetUserCredentials(ctx context.Context, email string) (*entity.User, error) {
+	return u.GetUserByEmail(ctx, email)
+}
+
+func (u *User) GetUserByID(ctx context.Context, id int) (*entity.User, error) {
+	result := entity.User{ID: id}
+	res := u.db.First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
 func (u *User) GetProfileByID(ctx context.Context, id int) (*entity.Profile, error) {
 	result := entity.Profile{ID: id}
 	res := u.db.First(&result)
@@ -53,28 +99,209 @@ func (u *User) GetProfileByID(ctx context.Context, id int) (*entity.Profile, err
 
 func (u *User) GetStoreByID(ctx context.Context, id int) (*entity.Store, error) {
 	result := entity.Store{ID: id}
-	res := u.db.First(&result)
+	res := u.db.Unscoped().
+		Preload("User").
+		Preload("Hours").
+		Preload("Holidays").
+		Preload("Categories").
+		First(&result)
 	if res.Error != nil {
 		return nil, res.Error
 	}
 	return &result, nil
 }
 
-func (u *User) SearchStore(ctx context.Context, name string) ([]entity.Store, error) {
+// SearchStore finds a page of approved stores, or any for AnyStatus, sorted by
+// relevance when searching terms and by name otherwise.
+func (u *User) SearchStore(ctx context.Context, filter *entity.StoreFilter) ([]entity.Store, string, error) {
+	sort := filter.Sort
+	if sort == "" || (sort == entity.SortRelevance && len(filter.Terms) == 0) {
+		sort = entity.SortName
+		if len(filter.Terms) > 0 {
+			sort = entity.SortRelevance
+		}
+	}
+	after, err := decodeCursor(filter.Page.Cursor, sort)
+	if err != nil {
+		return nil, "", err
+	}
+
 	var result []entity.Store
-	res := u.db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(name)+"%").Find(&result)
+	query := u.storeSearch(filter, "").
+		Preload("Hours").
+		Preload("Holidays").
+		Preload("Categories")
+	if len(filter.Terms) > 0 {
+		query = query.Select("stores.*, "+storeMatch+" AS relevance", fullTextQuery(filter.Terms))
+	}
+
+	offset := 0
+	switch sort {
+	case entity.SortName:
+		query = query.Order("name").Order("id")
+		if after != nil {
+			query = query.Where("name > ? OR (name = ? AND id > ?)", after.Name, after.Name, after.ID)
+		}
+	case entity.SortCreatedAt:
+		query = query.Order("created_at DESC").Order("id DESC")
+		if after != nil {
+			query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", after.Time, after.Time, after.ID)
+		}
+	case entity.SortRating:
+		query = query.Order("rating_avg DESC").Order("rating_count DESC").Order("id")
+		if after != nil {
+			query = query.Where(
+				"rating_avg < ? OR (rating_avg = ? AND (rating_count < ? OR (rating_count = ? AND id > ?)))",
+				after.Rating, after.Rating, after.Count, after.Count, after.ID,
+			)
+		}
+	case entity.SortRelevance:
+		query = query.Order("relevance DESC").Order("id")
+		if after != nil {
+			offset = after.Offset
+		}
+	}
+
+	limit := pageSize(filter.Page.Limit)
+	res := query.Offset(offset).Limit(limit + 1).Find(&result)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+	if len(result) <= limit {
+		return result, "", nil
+	}
+
+	result = result[:limit]
+	last := result[limit-1]
+	next := cursor{Sort: sort, ID: last.ID}
+	switch sort {
+	case entity.SortName:
+		next.Name = last.Name
+	case entity.SortCreatedAt:
+		next.Time = last.CreatedAt
+	case entity.SortRating:
+		next.Rating, next.Count = last.RatingAvg, last.RatingCount
+	case entity.SortRelevance:
+		next.Offset = offset + limit
+	}
+	return result, next.encode(), nil
+}
+
+// StoreFacets counts the approved stores matching the filter by state, city
+// and category. Each facet ignores its own filter, so the counts show what
+// picking another value would find.
+func (u *User) StoreFacets(ctx context.Context, filter *entity.StoreFilter) (*entity.StoreFacets, error) {
+	facets := &entity.StoreFacets{
+		State:    []entity.Facet{},
+		City:     []entity.Facet{},
+		Category: []entity.Facet{},
+	}
+
+	res := u.storeSearch(filter, entity.FacetState).
+		Select("stores.state AS value, COUNT(*) AS count").
+		Where("stores.state <> ''").
+		Group("stores.state").
+		Order("count DESC").
+		Order("value").
+		Limit(maxFacetValues).
+		Scan(&facets.State)
 	if res.Error != nil {
 		return nil, res.Error
 	}
-	return result, nil
+
+	res = u.storeSearch(filter, entity.FacetCity).
+		Select("stores.city AS value, COUNT(*) AS count").
+		Where("stores.city <> ''").
+		Group("stores.city").
+		Order("count DESC").
+		Order("value").
+		Limit(maxFacetValues).
+		Scan(&facets.City)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	res = u.storeSearch(filter, entity.FacetCategory).
+		Joins("JOIN store_categories ON store_categories.store_id = stores.id").
+		Joins("JOIN categories ON categories.id = store_categories.category_id").
+		Select("categories.id AS value, categories.name AS label, COUNT(*) AS count").
+		Group("categories.id").
+		Group("categories.name").
+		Order("count DESC").
+		Order("label").
+		Limit(maxFacetValues).
+		Scan(&facets.Category)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return facets, nil
+}
+
+// maxFacetValues bounds the values listed by each facet, the most common first.
+const maxFacetValues = 20
+
+// storeSearch filters the approved stores, or every store for AnyStatus, by
+// the search filter, leaving out the filter of the facet being counted, if any.
+func (u *User) storeSearch(filter *entity.StoreFilter, facet string) *gorm.DB {
+	query := u.db.Model(&entity.Store{})
+	if !filter.AnyStatus {
+		query = query.Where("stores.status = ?", entity.StatusApproved)
+	}
+	if len(filter.Terms) > 0 {
+		query = query.Where(storeMatch, fullTextQuery(filter.Terms))
+	} else {
+		query = query.Where("stores.search_text LIKE ?", "%"+likeEscaper.Replace(normalize.Fold(filter.Name))+"%")
+	}
+	if filter.CategoryID != 0 && facet != entity.FacetCategory {
+		query = query.Where(
+			"stores.id IN (?)",
+			u.db.Table("store_categories").Select("store_id").Where("category_id = ?", filter.CategoryID),
+		)
+	}
+	if filter.Tag != "" {
+		query = query.Where("FIND_IN_SET(?, stores.tags)", filter.Tag)
+	}
+	if filter.State != "" && facet != entity.FacetState {
+		query = query.Where("stores.state = ?", filter.State)
+	}
+	if filter.City != "" && facet != entity.FacetCity {
+		query = query.Where("stores.city = ?", filter.City)
+	}
+	return query
+}
+
+// likeEscaper escapes the LIKE wildcards of user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// storeMatch matches the ft_stores_search index, its arguments are the query.
+const storeMatch = "MATCH(stores.search_text) AGAINST(? IN BOOLEAN MODE)"
+
+// storeSearchText folds the searchable fields of a store, search terms are folded the same way.
+func storeSearchText(store *entity.Store) string {
+	return normalize.SearchText(store.Name, store.City, store.Description, strings.Join(store.Tags, " "))
 }
 
-func (u *User) UpdateProfile(ctx context.Context, id int, profile *entity.Profile) error {
+// fullTextQuery requires every term, as a word prefix, in boolean mode.
+func fullTextQuery(terms []string) string {
+	query := make([]string, 0, len(terms))
+	for _, term := range terms {
+		query = append(query, "+"+term+"*")
+	}
+	return strings.Join(query, " ")
+}
+
+// UpdateProfile updates the profile fields when it's still at the given
+// version, zero skips the check, and bumps its version.
+func (u *User) UpdateProfile(ctx context.Context, id int, version int, profile *entity.Profile) error {
 	result := entity.Profile{ID: id}
 	res := u.db.First(&result)
 	if res.Error != nil {
 		return res.Error
 	}
+	if version != 0 && result.Version != version {
+		return entity.ErrVersionMismatch
+	}
 
 	result.Address = profile.Address
 	result.City = profile.City
@@ -82,16 +309,96 @@ func (u *User) UpdateProfile(ctx context.Context, id int, profile *entity.Profil
 	result.State = profile.State
 	result.ZipCode = profile.ZipCode
 	result.Name = profile.Name
+	result.CPF = profile.CPF
+	columns := profileColumns
+	if result.Phone != profile.Phone {
+		result.Phone = profile.Phone
+		result.PhoneVerified = false
+		columns = append(columns[:len(columns):len(columns)], "phone_verified")
+	}
+	result.Version++
 
-	res = u.db.Save(result)
+	res = u.db.Model(&result).
+		Where("version = ?", result.Version-1).
+		Select(columns).
+		Updates(&result)
 	if res.Error != nil {
 		return res.Error
 	}
+	if res.RowsAffected == 0 {
+		return entity.ErrVersionMismatch
+	}
+	profile.Version = result.Version
 	return nil
 }
 
-func (u *User) GetUserStore(ctx context.Context, email string) (*entity.Store, error) {
-	store := entity.Store{}
+// UpdateStore updates the store fields and replaces its categories when it's
+// still at the given version, zero skips the check, and bumps its version.
+func (u *User) UpdateStore(ctx context.Context, id int, version int, store *entity.Store) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		result := entity.Store{ID: id}
+		res := tx.First(&result)
+		if res.Error != nil {
+			return res.Error
+		}
+		if version != 0 && result.Version != version {
+			return entity.ErrVersionMismatch
+		}
+
+		if store.Slug != "" && store.Slug != result.Slug {
+			if result.Slug != "" {
+				res = tx.Create(&entity.StoreSlug{Slug: result.Slug, StoreID: id})
+				if res.Error != nil {
+					return res.Error
+				}
+			}
+			res = tx.Where("slug = ? AND store_id = ?", store.Slug, id).Delete(&entity.StoreSlug{})
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Slug = store.Slug
+		}
+
+		result.Name = store.Name
+		result.Description = store.Description
+		result.CNPJ = store.CNPJ
+		result.CPF = store.CPF
+		columns := storeColumns
+		if result.Phone != store.Phone {
+			result.Phone = store.Phone
+			result.PhoneVerified = false
+			columns = append(columns[:len(columns):len(columns)], "phone_verified")
+		}
+		result.Address = store.Address
+		result.Block = store.Block
+		result.ZipCode = store.ZipCode
+		result.City = store.City
+		result.State = store.State
+		result.PhotoPath = store.PhotoPath
+		result.Latitude = store.Latitude
+		result.Longitude = store.Longitude
+		result.Tags = store.Tags
+		result.SearchText = storeSearchText(&result)
+		result.Version++
+
+		res = tx.Model(&result).
+			Where("version = ?", result.Version-1).
+			Select(columns).
+			Updates(&result)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return entity.ErrVersionMismatch
+		}
+		store.Version = result.Version
+
+		return tx.Model(&result).Omit("Categories.*").Association("Categories").Replace(store.Categories)
+	})
+}
+
+func (u *User) GetUserStores(ctx context.Context, email string) ([]entity.StoreMember, error) {
+	var result []entity.StoreMember
 	user := entity.User{}
 
 	res := u.db.Where("email = ?", email).First(&user)
@@ -99,12 +406,16 @@ func (u *User) GetUserStore(ctx context.Context, email string) (*entity.Store, e
 		return nil, res.Error
 	}
 
-	res = u.db.Where("user_id = ?", user.ID).First(&store)
+	res = u.db.Preload("Store").
+		Joins("JOIN stores ON stores.id = store_members.store_id AND stores.archived_at IS NULL").
+		Where("store_members.user_id = ?", user.ID).
+		Order("store_members.id").
+		Find(&result)
 	if res.Error != nil {
 		return nil, res.Error
 	}
 
-	return &store, nil
+	return result, nil
 }
 
 func (u *User) GetUserProfile(ctx context.Context, email string) (*entity.Profile, error) {
@@ -123,3 +434,899 @@ func (u *User) GetUserProfile(ctx context.Context, email string) (*entity.Profil
 
 	return &profile, nil
 }
+
+func (u *User) ArchiveStore(ctx context.Context, id int) error {
+	res := u.db.Delete(&entity.Store{ID: id})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (u *User) RestoreStore(ctx context.Context, id int, since time.Time) error {
+	res := u.db.Unscoped().
+		Model(&entity.Store{}).
+		Where("id = ? AND archived_at >= ?", id, since).
+		Update("archived_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (u *User) GetArchivedStores(ctx context.Context, before time.Time) ([]entity.Store, error) {
+	var result []entity.Store
+	res := u.db.Unscoped().
+		Preload("User").
+		Where("archived_at IS NOT NULL AND archived_at < ?", before).
+		Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+// PurgeStore hard-deletes an archived store and its owning user when the user
+// has no profile, admin role, other store, membership or review, the rows they
+// own are removed by the ON DELETE CASCADE foreign keys. The cleanup
+// runs before committing, told whether the user goes too, and its error rolls
+// the purge back so it can be retried. It reports whether the user was deleted.
+func (u *User) PurgeStore(ctx context.Context, store *entity.Store, cleanup func(userDeleted bool) error) (bool, error) {
+	deleted := false
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Delete(&entity.Store{ID: store.ID})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		var user entity.User
+		res = tx.First(&user, store.UserID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if user.IsAdmin {
+			return cleanup(false)
+		}
+
+		var count int64
+		res = tx.Model(&entity.Profile{}).Where("user_id = ?", user.ID).Count(&count)
+		if res.Error != nil {
+			return res.Error
+		}
+		if count > 0 {
+			return cleanup(false)
+		}
+		res = tx.Unscoped().Model(&entity.Store{}).Where("user_id = ?", user.ID).Count(&count)
+		if res.Error != nil {
+			return res.Error
+		}
+		if count > 0 {
+			return cleanup(false)
+		}
+		res = tx.Model(&entity.StoreMember{}).Where("user_id = ?", user.ID).Count(&count)
+		if res.Error != nil {
+			return res.Error
+		}
+		if count > 0 {
+			return cleanup(false)
+		}
+		res = tx.Model(&entity.Review{}).Where("user_id = ?", user.ID).Count(&count)
+		if res.Error != nil {
+			return res.Error
+		}
+		if count > 0 {
+			return cleanup(false)
+		}
+
+		res = tx.Delete(&user)
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = true
+		return cleanup(true)
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
+
+func (u *User) CreateMember(ctx context.Context, member *entity.StoreMember) error {
+	return u.db.Omit(clause.Associations).Create(member).Error
+}
+
+func (u *User) GetMember(ctx context.Context, storeID int, userID int) (*entity.StoreMember, error) {
+	var result entity.StoreMember
+	res := u.db.Where("store_id = ? AND user_id = ?", storeID, userID).First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+func (u *User) GetMembers(ctx context.Context, storeID int) ([]entity.StoreMember, error) {
+	var result []entity.StoreMember
+	res := u.db.Preload("User").Where("store_id = ?", storeID).Order("id").Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+func (u *User) DeleteMember(ctx context.Context, storeID int, userID int) error {
+	res := u.db.Where("store_id = ? AND user_id = ?", storeID, userID).Delete(&entity.StoreMember{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (u *User) GetAddresses(ctx context.Context, userID int) ([]entity.Address, error) {
+	var result []entity.Address
+	res := u.db.Where("user_id = ?", userID).Order("id").Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+// CreateAddress creates an address, the first address of an user becomes
+// the default one and a new default unsets the previous.
+func (u *User) CreateAddress(ctx context.Context, address *entity.Address) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		res := tx.Model(&entity.Address{}).Where("user_id = ?", address.UserID).Count(&count)
+		if res.Error != nil {
+			return res.Error
+		}
+		if count == 0 {
+			address.DefaultShipping = true
+			address.DefaultBilling = true
+		}
+
+		err := clearDefaults(tx, address)
+		if err != nil {
+			return err
+		}
+		return tx.Create(address).Error
+	})
+}
+
+func (u *User) UpdateAddress(ctx context.Context, address *entity.Address) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		var current entity.Address
+		res := tx.Where("id = ? AND user_id = ?", address.ID, address.UserID).First(&current)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		err := clearDefaults(tx, address)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&current).
+			Select("*").
+			Omit("id", "user_id").
+			Updates(address).Error
+	})
+}
+
+// DeleteAddress deletes an address, its default flags move to the oldest remaining address.
+func (u *User) DeleteAddress(ctx context.Context, userID int, id int) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		var address entity.Address
+		res := tx.Where("id = ? AND user_id = ?", id, userID).First(&address)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		res = tx.Delete(&address)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		flags := map[string]interface{}{}
+		if address.DefaultShipping {
+			flags["default_shipping"] = true
+		}
+		if address.DefaultBilling {
+			flags["default_billing"] = true
+		}
+		if len(flags) == 0 {
+			return nil
+		}
+
+		var next entity.Address
+		res = tx.Where("user_id = ?", userID).Order("id").Limit(1).Find(&next)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&next).Updates(flags).Error
+	})
+}
+
+func (u *User) GetDefaultAddress(ctx context.Context, email string) (*entity.Address, error) {
+	var result entity.Address
+	res := u.db.Joins("JOIN users ON users.id = addresses.user_id").
+		Where("users.email = ? AND addresses.default_shipping", email).
+		First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+func clearDefaults(tx *gorm.DB, address *entity.Address) error {
+	if address.DefaultShipping {
+		res := tx.Model(&entity.Address{}).
+			Where("user_id = ? AND id <> ?", address.UserID, address.ID).
+			Update("default_shipping", false)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	if address.DefaultBilling {
+		res := tx.Model(&entity.Address{}).
+			Where("user_id = ? AND id <> ?", address.UserID, address.ID).
+			Update("default_billing", false)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+// CreatePhoneVerification replaces the pending verification of the user phone.
+func (u *User) CreatePhoneVerification(ctx context.Context, verification *entity.PhoneVerification) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND phone = ?", verification.UserID, verification.Phone).
+			Delete(&entity.PhoneVerification{})
+		if res.Error != nil {
+			return res.Error
+		}
+		return tx.Create(verification).Error
+	})
+}
+
+func (u *User) GetPhoneVerification(ctx context.Context, userID int, phone string) (*entity.PhoneVerification, error) {
+	var result entity.PhoneVerification
+	res := u.db.Where("user_id = ? AND phone = ?", userID, phone).First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+func (u *User) IncrementPhoneAttempts(ctx context.Context, id int) error {
+	return u.db.Model(&entity.PhoneVerification{ID: id}).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// ConfirmPhone marks the phone verified on the user profile and on the stores
+// the user owns or manages, bumping their versions.
+func (u *User) ConfirmPhone(ctx context.Context, userID int, phone string) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND phone = ?", userID, phone).Delete(&entity.PhoneVerification{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		res = tx.Model(&entity.Profile{}).
+			Where("user_id = ? AND phone = ?", userID, phone).
+			Updates(map[string]interface{}{
+				"phone_verified": true,
+				"version":        gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		managed := tx.Model(&entity.StoreMember{}).
+			Select("store_id").
+			Where("user_id = ? AND role IN ?", userID, []string{entity.RoleOwner, entity.RoleManager})
+		return tx.Model(&entity.Store{}).
+			Where("phone = ? AND id IN (?)", phone, managed).
+			Updates(map[string]interface{}{
+				"phone_verified": true,
+				"version":        gorm.Expr("version + 1"),
+			}).Error
+	})
+}
+
+// UpdateSchedule replaces the timezone, vacation, hours and holidays of a store
+// when it's still at the given version, zero skips the check, and bumps its version.
+func (u *User) UpdateSchedule(ctx context.Context, id int, version int, schedule *entity.StoreSchedule) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		store := entity.Store{ID: id}
+		res := tx.First(&store)
+		if res.Error != nil {
+			return res.Error
+		}
+		if version != 0 && store.Version != version {
+			return entity.ErrVersionMismatch
+		}
+
+		res = tx.Model(&store).
+			Where("version = ?", store.Version).
+			Updates(map[string]interface{}{
+				"timezone":  schedule.Timezone,
+				"vacation":  schedule.Vacation,
+				"reopen_at": schedule.ReopenAt,
+				"version":   store.Version + 1,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return entity.ErrVersionMismatch
+		}
+		schedule.Version = store.Version + 1
+
+		res = tx.Where("store_id = ?", id).Delete(&entity.StoreHours{})
+		if res.Error != nil {
+			return res.Error
+		}
+		res = tx.Where("store_id = ?", id).Delete(&entity.StoreHoliday{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		for i := range schedule.Hours {
+			schedule.Hours[i].ID = 0
+			schedule.Hours[i].StoreID = id
+		}
+		for i := range schedule.Holidays {
+			schedule.Holidays[i].ID = 0
+			schedule.Holidays[i].StoreID = id
+		}
+		if len(schedule.Hours) > 0 {
+			res = tx.Create(&schedule.Hours)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		if len(schedule.Holidays) > 0 {
+			res = tx.Create(&schedule.Holidays)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+}
+
+// NearbyStores finds the located stores inside a box around the point, the
+// caller refines it to the radius.
+func (u *User) NearbyStores(ctx context.Context, minLat, maxLat, minLng, maxLng float64) ([]entity.Store, error) {
+	var result []entity.Store
+	res := u.db.Preload("Hours").
+		Preload("Holidays").
+		Preload("Categories").
+		Where("status = ?", entity.StatusApproved).
+		Where("latitude BETWEEN ? AND ?", minLat, maxLat).
+		Where("longitude BETWEEN ? AND ?", minLng, maxLng).
+		Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+func (u *User) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	var result []entity.Category
+	res := u.db.Order("name").Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+func (u *User) GetCategoriesByIDs(ctx context.Context, ids []int) ([]entity.Category, error) {
+	var result []entity.Category
+	res := u.db.Where("id IN ?", ids).Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+func (u *User) CreateCategory(ctx context.Context, category *entity.Category) error {
+	return u.db.Create(category).Error
+}
+
+func (u *User) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	result := entity.Category{ID: category.ID}
+	res := u.db.First(&result)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	result.Name = category.Name
+	return u.db.Save(&result).Error
+}
+
+// DeleteCategory deletes a category and unlinks it from its stores.
+func (u *User) DeleteCategory(ctx context.Context, id int) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec("DELETE FROM store_categories WHERE category_id = ?", id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		res = tx.Delete(&entity.Category{ID: id})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// SaveReview creates or edits the user review of a store and refreshes the store
+// rating, reporting whether it was created.
+func (u *User) SaveReview(ctx context.Context, review *entity.Review) (bool, error) {
+	created := false
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		var current entity.Review
+		res := tx.Where("store_id = ? AND user_id = ?", review.StoreID, review.UserID).Limit(1).Find(&current)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			res = tx.Create(review)
+			created = true
+		} else {
+			current.Rating = review.Rating
+			current.Text = review.Text
+			res = tx.Save(&current)
+			*review = current
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+
+		return refreshRating(tx, review.StoreID)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+func (u *User) GetReview(ctx context.Context, id int) (*entity.Review, error) {
+	result := entity.Review{ID: id}
+	res := u.db.First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+// GetReviews finds a page of the visible reviews of a store, latest first.
+func (u *User) GetReviews(ctx context.Context, storeID int, page entity.PageRequest) ([]entity.Review, string, error) {
+	after, err := decodeCursor(page.Cursor, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var result []entity.Review
+	query := u.db.Where("store_id = ? AND NOT hidden", storeID).Order("updated_at DESC").Order("id DESC")
+	if after != nil {
+		query = query.Where("updated_at < ? OR (updated_at = ? AND id < ?)", after.Time, after.Time, after.ID)
+	}
+
+	limit := pageSize(page.Limit)
+	res := query.Limit(limit + 1).Find(&result)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+	if len(result) <= limit {
+		return result, "", nil
+	}
+
+	result = result[:limit]
+	last := result[limit-1]
+	return result, cursor{ID: last.ID, Time: last.UpdatedAt}.encode(), nil
+}
+
+func (u *User) ReplyReview(ctx context.Context, id int, reply string) error {
+	now := time.Now()
+	return u.db.Model(&entity.Review{ID: id}).Updates(map[string]interface{}{
+		"reply":      reply,
+		"replied_at": &now,
+	}).Error
+}
+
+// HideReview hides or shows a review and refreshes the store rating.
+func (u *User) HideReview(ctx context.Context, review *entity.Review, hidden bool) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(review).Update("hidden", hidden)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		return refreshRating(tx, review.StoreID)
+	})
+}
+
+// refreshRating recomputes the store rating from its visible reviews and bumps
+// the store version.
+func refreshRating(tx *gorm.DB, storeID int) error {
+	var rating struct {
+		Avg   float64
+		Count int
+	}
+	res := tx.Model(&entity.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS avg, COUNT(*) AS count").
+		Where("store_id = ? AND NOT hidden", storeID).
+		Scan(&rating)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	return tx.Unscoped().Model(&entity.Store{ID: storeID}).Updates(map[string]interface{}{
+		"rating_avg":   rating.Avg,
+		"rating_count": rating.Count,
+		"version":      gorm.Expr("version + 1"),
+	}).Error
+}
+
+// AddFavorite makes an user follow a store, reporting whether it wasn't
+// followed yet.
+func (u *User) AddFavorite(ctx context.Context, favorite *entity.Favorite) (bool, error) {
+	res := u.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(favorite)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (u *User) DeleteFavorite(ctx context.Context, userID int, storeID int) error {
+	return u.db.Where("user_id = ? AND store_id = ?", userID, storeID).Delete(&entity.Favorite{}).Error
+}
+
+// GetFavorites finds a page of the stores an user follows, latest first.
+func (u *User) GetFavorites(ctx context.Context, userID int, page entity.PageRequest) ([]entity.Favorite, string, error) {
+	after, err := decodeCursor(page.Cursor, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var result []entity.Favorite
+	query := u.db.Preload("Store").
+		Joins("JOIN stores ON stores.id = favorites.store_id AND stores.archived_at IS NULL").
+		Where("favorites.user_id = ?", userID).
+		Order("favorites.created_at DESC").
+		Order("favorites.store_id DESC")
+	if after != nil {
+		query = query.Where(
+			"favorites.created_at < ? OR (favorites.created_at = ? AND favorites.store_id < ?)",
+			after.Time, after.Time, after.ID,
+		)
+	}
+
+	limit := pageSize(page.Limit)
+	res := query.Limit(limit + 1).Find(&result)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+	if len(result) <= limit {
+		return result, "", nil
+	}
+
+	result = result[:limit]
+	last := result[limit-1]
+	return result, cursor{ID: last.StoreID, Time: last.CreatedAt}.encode(), nil
+}
+
+func (u *User) CountFollowers(ctx context.Context, storeID int) (int64, error) {
+	var count int64
+	res := u.db.Model(&entity.Favorite{}).Where("store_id = ?", storeID).Count(&count)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return count, nil
+}
+
+func (u *User) GetFollowers(ctx context.Context, storeID int) ([]entity.User, error) {
+	var result []entity.User
+	res := u.db.Joins("JOIN favorites ON favorites.user_id = users.id").
+		Where("favorites.store_id = ?", storeID).
+		Order("users.id").
+		Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+// GetStoresByStatus finds a page of the stores in a verification status,
+// the longest waiting first.
+func (u *User) GetStoresByStatus(ctx context.Context, status string, page entity.PageRequest) ([]entity.Store, string, error) {
+	after, err := decodeCursor(page.Cursor, status)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var result []entity.Store
+	query := u.db.Preload("User").
+		Preload("Categories").
+		Where("status = ?", status).
+		Order("submitted_at").
+		Order("id")
+	if after != nil {
+		query = query.Where("submitted_at > ? OR (submitted_at = ? AND id > ?)", after.Time, after.Time, after.ID)
+	}
+
+	limit := pageSize(page.Limit)
+	res := query.Limit(limit + 1).Find(&result)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+	if len(result) <= limit {
+		return result, "", nil
+	}
+
+	result = result[:limit]
+	last := result[limit-1]
+	next := cursor{Sort: status, ID: last.ID}
+	if last.SubmittedAt != nil {
+		next.Time = *last.SubmittedAt
+	}
+	return result, next.encode(), nil
+}
+
+// UpdateStoreStatus saves the verification fields of a store and bumps its version.
+func (u *User) UpdateStoreStatus(ctx context.Context, store *entity.Store) error {
+	res := u.db.Model(store).Updates(map[string]interface{}{
+		"status":           store.Status,
+		"rejection_reason": store.RejectionReason,
+		"submitted_at":     store.SubmittedAt,
+		"reviewed_at":      store.ReviewedAt,
+		"version":          gorm.Expr("version + 1"),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	store.Version++
+	return nil
+}
+
+func (u *User) GetStoreBySlug(ctx context.Context, slug string) (*entity.Store, error) {
+	var result entity.Store
+	res := u.db.Unscoped().Where("slug = ?", slug).First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+func (u *User) GetSlugRedirect(ctx context.Context, slug string) (*entity.StoreSlug, error) {
+	result := entity.StoreSlug{Slug: slug}
+	res := u.db.First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+// SlugTaken tells whether a slug is used, now or in the past, by a store other than storeID.
+func (u *User) SlugTaken(ctx context.Context, slug string, storeID int) (bool, error) {
+	var count int64
+	res := u.db.Unscoped().Model(&entity.Store{}).
+		Where("slug = ? AND id <> ?", slug, storeID).
+		Count(&count)
+	if res.Error != nil || count > 0 {
+		return count > 0, res.Error
+	}
+
+	res = u.db.Model(&entity.StoreSlug{}).
+		Where("slug = ? AND store_id <> ?", slug, storeID).
+		Count(&count)
+	return count > 0, res.Error
+}
+
+// GetStoresWithoutSlug lists the id and name of the stores still missing a slug.
+func (u *User) GetStoresWithoutSlug(ctx context.Context) ([]entity.Store, error) {
+	var result []entity.Store
+	res := u.db.Unscoped().
+		Select("id", "name").
+		Where("slug IS NULL").
+		Order("id").
+		Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+// UpdateStoreSlug sets the slug of a store that doesn't have one yet.
+func (u *User) UpdateStoreSlug(ctx context.Context, id int, slug string) error {
+	return u.db.Unscoped().Model(&entity.Store{ID: id}).
+		Where("slug IS NULL").
+		UpdateColumn("slug", slug).Error
+}
+
+// GetStoreSuggestions lists the name and slug of the public stores.
+func (u *User) GetStoreSuggestions(ctx context.Context) ([]entity.StoreSuggestion, error) {
+	var result []entity.StoreSuggestion
+	res := u.db.Model(&entity.Store{}).
+		Select("id, name, slug").
+		Where("status = ?", entity.StatusApproved).
+		Find(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return result, nil
+}
+
+func (u *User) UpdateProfileAvatar(ctx context.Context, id int, avatar string) error {
+	return u.db.Model(&entity.Profile{ID: id}).Updates(map[string]interface{}{
+		"avatar_path": avatar,
+		"version":     gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (u *User) GetPreferences(ctx context.Context, userID int) (*entity.Preferences, error) {
+	result := entity.Preferences{UserID: userID}
+	res := u.db.First(&result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &result, nil
+}
+
+func (u *User) SavePreferences(ctx context.Context, preferences *entity.Preferences) error {
+	return u.db.Save(preferences).Error
+}
+
+// SearchUsers finds a page of users matching an admin search, newest first,
+// along with their profiles and stores.
+func (u *User) SearchUsers(ctx context.Context, filter *entity.UserFilter) ([]entity.UserSummary, string, error) {
+	after, err := decodeCursor(filter.Page.Cursor, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := u.db.Model(&entity.User{}).Order("id DESC")
+	if after != nil {
+		query = query.Where("id < ?", after.ID)
+	}
+	if filter.EmailPrefix != "" {
+		query = query.Where("email LIKE ?", likeEscaper.Replace(filter.EmailPrefix)+"%")
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+
+	switch filter.Role {
+	case "":
+	case entity.RoleAdmin:
+		query = query.Where("is_admin")
+	case entity.RoleCustomer:
+		query = query.Where("EXISTS (?)", u.db.Table("profiles").Select("1").Where("profiles.user_id = users.id"))
+	default:
+		query = query.Where("EXISTS (?)", u.db.Table("store_members").Select("1").
+			Where("store_members.user_id = users.id AND store_members.role = ?", filter.Role))
+	}
+
+	if filter.Status != "" {
+		stores := u.db.Table("store_members").Select("1").
+			Joins("JOIN stores ON stores.id = store_members.store_id").
+			Where("store_members.user_id = users.id")
+		if filter.Status == entity.StatusArchived {
+			stores = stores.Where("stores.archived_at IS NOT NULL")
+		} else {
+			stores = stores.Where("stores.status = ? AND stores.archived_at IS NULL", filter.Status)
+		}
+		query = query.Where("EXISTS (?)", stores)
+	}
+
+	if filter.City != "" || filter.State != "" {
+		profiles := u.db.Table("profiles").Select("1").Where("profiles.user_id = users.id")
+		stores := u.db.Table("store_members").Select("1").
+			Joins("JOIN stores ON stores.id = store_members.store_id").
+			Where("store_members.user_id = users.id")
+		if filter.City != "" {
+			profiles = profiles.Where("profiles.city = ?", filter.City)
+			stores = stores.Where("stores.city = ?", filter.City)
+		}
+		if filter.State != "" {
+			profiles = profiles.Where("profiles.state = ?", filter.State)
+			stores = stores.Where("stores.state = ?", filter.State)
+		}
+		query = query.Where("EXISTS (?) OR EXISTS (?)", profiles, stores)
+	}
+
+	var users []entity.User
+	limit := pageSize(filter.Page.Limit)
+	res := query.Limit(limit + 1).Find(&users)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+
+	next := ""
+	if len(users) > limit {
+		users = users[:limit]
+		next = cursor{ID: users[limit-1].ID}.encode()
+	}
+	if len(users) == 0 {
+		return []entity.UserSummary{}, next, nil
+	}
+
+	ids := make([]int, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+
+	var profiles []entity.Profile
+	res = u.db.Where("user_id IN ?", ids).Find(&profiles)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+	var members []entity.StoreMember
+	res = u.db.Preload("Store", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("user_id IN ?", ids).Order("id").Find(&members)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+
+	result := make([]entity.UserSummary, 0, len(users))
+	for _, user := range users {
+		summary := entity.UserSummary{User: user}
+		for i := range profiles {
+			if profiles[i].UserID == user.ID {
+				summary.Profile = &profiles[i]
+			}
+		}
+		for _, member := range members {
+			if member.UserID == user.ID {
+				summary.Stores = append(summary.Stores, member)
+			}
+		}
+		result = append(result, summary)
+	}
+	return result, next, nil
+}
+
+// RefreshSearchText folds again the search text of the stores that don't
+// match it, like the ones backfilled by the migration, returning how many
+// were updated.
+func (u *User) RefreshSearchText(ctx context.Context) (int, error) {
+	var batch []entity.Store
+	updated := 0
+	res := u.db.Unscoped().
+		Select("id", "name", "city", "description", "tags", "search_text").
+		FindInBatches(&batch, 100, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				text := storeSearchText(&batch[i])
+				if text == batch[i].SearchText {
+					continue
+				}
+				res := u.db.Unscoped().Model(&batch[i]).
+					UpdateColumn("search_text", text)
+				if res.Error != nil {
+					return res.Error
+				}
+				updated++
+			}
+			return nil
+		})
+	return updated, res.Error
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrImageTooLarge is returned when an upload exceeds the configured limits.
+	ErrImageTooLarge = errors.New("image too large")
+	// ErrInvalidImage is returned when an upload isn't a png, jpeg or gif image.
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+type AvatarConfig struct {
+	Sizes     []int  `yaml:"sizes"`
+	MaxBytes  int64  `yaml:"max_bytes"`
+	MaxPixels int    `yaml:"max_pixels"`
+	BaseURL   string `yaml:"base_url"`
+}
+
+// Avatar crops uploaded pictures to a square and stores them in every
+// configured size, as `<name>_<size>.png` inside the uploads directory.
+type Avatar struct {
+	cfg *AvatarConfig
+}
+
+func NewAvatar(cfg *AvatarConfig) *Avatar {
+	return &Avatar{
+		cfg: cfg,
+	}
+}
+
+// Save stores a new avatar and returns its name.
+func (a *Avatar) Save(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, a.cfg.MaxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > a.cfg.MaxBytes {
+		return "", ErrImageTooLarge
+	}
+
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidImage
+	}
+	if conf.Width*conf.Height > a.cfg.MaxPixels {
+		return "", ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidImage
+	}
+
+	square := cropSquare(img)
+	name := uuid.New().String()
+	for _, size := range a.cfg.Sizes {
+		err = a.write(fmt.Sprintf("%s_%d", name, size), resize(square, size))
+		if err != nil {
+			a.Delete(name)
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// Delete removes every size of an avatar, missing files are ignored.
+func (a *Avatar) Delete(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	name = filepath.Base(name)
+	for _, size := range a.cfg.Sizes {
+		err := os.Remove(filepath.Join(uploadDir, fmt.Sprintf("%s_%d.png", name, size)))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// URLs maps each size of an avatar to its public URL.
+func (a *Avatar) URLs(name string) map[string]string {
+	if name == "" {
+		return nil
+	}
+
+	result := make(map[string]string, len(a.cfg.Sizes))
+	for _, size := range a.cfg.Sizes {
+		result[strconv.Itoa(size)] = fmt.Sprintf("%s/view-file/%s_%d.png", a.cfg.BaseURL, name, size)
+	}
+	return result
+}
+
+// URL returns the public URL of the largest size of an avatar.
+func (a *Avatar) URL(name string) string {
+	if name == "" || len(a.cfg.Sizes) == 0 {
+		return ""
+	}
+
+	largest := a.cfg.Sizes[0]
+	for _, size := range a.cfg.Sizes {
+		if size > largest {
+			largest = size
+		}
+	}
+	return fmt.Sprintf("%s/view-file/%s_%d.png", a.cfg.BaseURL, name, largest)
+}
+
+func (a *Avatar) write(name string, img image.Image) error {
+	f, err := os.Create(filepath.Join(uploadDir, name+".png"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
+
+// cropSquare cuts the centered square of an image.
+func cropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+
+	result := image.NewNRGBA(image.Rect(0, 0, side, side))
+	for dy := 0; dy < side; dy++ {
+		for dx := 0; dx < side; dx++ {
+			result.Set(dx, dy, img.At(x+dx, y+dy))
+		}
+	}
+	return result
+}
+
+// resize scales a square image to size x size, averaging the source pixels
+// covered by each target pixel when shrinking.
+func resize(img image.Image, size int) image.Image {
+	side := img.Bounds().Dx()
+	result := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for ty := 0; ty < size; ty++ {
+		y0, y1 := ty*side/size, (ty+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for tx := 0; tx < size; tx++ {
+			x0, x1 := tx*side/size, (tx+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			i := result.PixOffset(tx, ty)
+			alpha := a / n
+			if alpha == 0 {
+				continue
+			}
+			// RGBA returns alpha premultiplied values, NRGBA stores them straight.
+			result.Pix[i] = uint8((r / n) * 0xffff / alpha >> 8)
+			result.Pix[i+1] = uint8((g / n) * 0xffff / alpha >> 8)
+			result.Pix[i+2] = uint8((b / n) * 0xffff / alpha >> 8)
+			result.Pix[i+3] = uint8(alpha >> 8)
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrAddressNotFound is returned when the geocoder can't place an address.
+var ErrAddressNotFound = errors.New("address not found")
+
+type GeocoderConfig struct {
+	Host      string        `yaml:"host"`
+	UserAgent string        `yaml:"user_agent"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// Geocoder finds the coordinates of a free-form address.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (float64, float64, error)
+}
+
+type Nominatim struct {
+	cfg    *GeocoderConfig
+	client *http.Client
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func NewNominatim(cfg *GeocoderConfig) *Nominatim {
+	return &Nominatim{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+// Geocode finds an address GET to `https://nominatim.openstreetmap.org/search?format=json&q=%s`
+func (n *Nominatim) Geocode(ctx context.Context, address string) (float64, float64, error) {
+	log := zap.NewNop()
+
+	if n.cfg.Host == "" {
+		return 0, 0, ErrAddressNotFound
+	}
+
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("limit", "1")
+	query.Set("countrycodes", "br")
+	query.Set("q", address)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, n.cfg.Host+"/search?"+query.Encode(), nil)
+	if err != nil {
+		log.Error(
+			"error creating geocoder request",
+			zap.Error(err),
+		)
+		return 0, 0, err
+	}
+	r.Header.Set("User-Agent", n.cfg.UserAgent)
+
+	resp, err := n.client.Do(r)
+	if err != nil {
+		log.Error(
+			"error making geocoder request",
+			zap.Error(err),
+		)
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error(
+			"error geocoding address",
+			zap.Any("status_code", resp.StatusCode),
+		)
+		return 0, 0, errors.New("error geocoding address")
+	}
+
+	var results []nominatimResult
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	if err != nil {
+		log.Error(
+			"error decoding geocoder response",
+			zap.Error(err),
+		)
+		return 0, 0, err
+	}
+	if len(results) == 0 {
+		return 0, 0, ErrAddressNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lng, nil
+}
@@ -142,3 +142,38 @@ func (k *Kong) createJWT(response *jwtResponse) (string, error) {
 
 	return tokenString, nil
 }
+
+// DeleteCustomer Deletes a customer on kong DELETE to `http://konghost:8001/consumers/%s`
+func (k *Kong) DeleteCustomer(email string) error {
+	log := zap.NewNop()
+
+	urlRequest := k.cfg.Host + k.cfg.ConsumerRequest + url.PathEscape(email)
+	r, err := http.NewRequest(http.MethodDelete, urlRequest, nil)
+	if err != nil {
+		log.Error(
+			"error creating kong request",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		log.Error(
+			"error making kong request",
+			zap.Error(err),
+		)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		log.Error(
+			"error deleting consumer",
+			zap.Any("status_code", resp.StatusCode),
+		)
+		return errors.New("error deleting consumer")
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/restore/user/entity"
+)
+
+type MailConfig struct {
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port"`
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	From     string        `yaml:"from"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Path     string        `yaml:"path"`
+}
+
+// Notifier sends a message to an user email.
+type Notifier interface {
+	Notify(email string, subject string, message string) error
+}
+
+// NewMailer creates the SMTP mailer, or the file mailer for tests when only a
+// path is set. Without either, sending fails instead of dropping the message.
+func NewMailer(cfg *MailConfig) Notifier {
+	switch {
+	case cfg.Host != "":
+		return NewSMTPMailer(cfg)
+	case cfg.Path != "":
+		return NewFileMailer(cfg)
+	default:
+		return DisabledMailer{}
+	}
+}
+
+// SMTPMailer sends the messages through an SMTP relay, upgrading to TLS when
+// the server offers it and authenticating when an username is set.
+type SMTPMailer struct {
+	cfg *MailConfig
+}
+
+func NewSMTPMailer(cfg *MailConfig) *SMTPMailer {
+	return &SMTPMailer{
+		cfg: cfg,
+	}
+}
+
+func (s *SMTPMailer) Notify(email string, subject string, message string) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port)), s.cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	if s.cfg.Timeout > 0 {
+		err = conn.SetDeadline(time.Now().Add(s.cfg.Timeout))
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: s.cfg.Host})
+		if err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(s.cfg.From)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(email)
+	if err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.cfg.From, email, mime.QEncoding.Encode("utf-8", subject), message)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileMailer writes the messages to a file instead of sending them, for tests.
+type FileMailer struct {
+	sink *fileSink
+}
+
+func NewFileMailer(cfg *MailConfig) *FileMailer {
+	return &FileMailer{
+		sink: &fileSink{path: cfg.Path},
+	}
+}
+
+func (f *FileMailer) Notify(email string, subject string, message string) error {
+	return f.sink.write(email, subject, message)
+}
+
+// DisabledMailer refuses every message, used when no mailer is configured.
+type DisabledMailer struct{}
+
+func (DisabledMailer) Notify(email string, subject string, message string) error {
+	return entity.ErrMailUnavailable
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	_ "embed"
+
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+)
+
+//go:embed data/cep_ranges.csv
+var cepRanges string
+
+type PostalConfig struct {
+	Host    string        `yaml:"host"`
+	Timeout time.Duration `yaml:"timeout"`
+	Offline bool          `yaml:"offline"`
+}
+
+// PostalLookup finds the address of a normalized 8 digits CEP.
+type PostalLookup interface {
+	Lookup(ctx context.Context, cep string) (*entity.PostalAddress, error)
+}
+
+// NewPostalLookup creates the ViaCEP client falling back to the offline dataset,
+// an empty host leaves only the offline dataset.
+func NewPostalLookup(cfg *PostalConfig) PostalLookup {
+	var lookups FallbackPostal
+	if cfg.Host != "" {
+		lookups = append(lookups, NewViaCEP(cfg))
+	}
+	if cfg.Offline || cfg.Host == "" {
+		lookups = append(lookups, NewOfflinePostal())
+	}
+	return lookups
+}
+
+// FallbackPostal tries each lookup in order until one finds the CEP.
+type FallbackPostal []PostalLookup
+
+func (f FallbackPostal) Lookup(ctx context.Context, cep string) (*entity.PostalAddress, error) {
+	err := entity.ErrCEPNotFound
+	for _, lookup := range f {
+		var address *entity.PostalAddress
+		address, err = lookup.Lookup(ctx, cep)
+		if err == nil {
+			return address, nil
+		}
+	}
+	return nil, err
+}
+
+type ViaCEP struct {
+	cfg    *PostalConfig
+	client *http.Client
+}
+
+type viaCEPResponse struct {
+	Cep        string `json:"cep"`
+	Logradouro string `json:"logradouro"`
+	Bairro     string `json:"bairro"`
+	Localidade string `json:"localidade"`
+	Uf         string `json:"uf"`
+	Erro       bool   `json:"erro"`
+}
+
+func NewViaCEP(cfg *PostalConfig) *ViaCEP {
+	return &ViaCEP{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+// Lookup finds a CEP GET to `https://viacep.com.br/ws/%s/json/`
+func (v *ViaCEP) Lookup(ctx context.Context, cep string) (*entity.PostalAddress, error) {
+	log := zap.NewNop()
+
+	urlRequest := v.cfg.Host + fmt.Sprintf("/ws/%s/json/", cep)
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, urlRequest, nil)
+	if err != nil {
+		log.Error(
+			"error creating postal request",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	resp, err := v.client.Do(r)
+	if err != nil {
+		log.Error(
+			"error making postal request",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error(
+			"error looking up CEP",
+			zap.Any("status_code", resp.StatusCode),
+		)
+		return nil, errors.New("error looking up CEP")
+	}
+
+	result := viaCEPResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		log.Error(
+			"error decoding postal response",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if result.Erro {
+		return nil, entity.ErrCEPNotFound
+	}
+
+	return &entity.PostalAddress{
+		ZipCode: cep,
+		Address: result.Logradouro,
+		Block:   result.Bairro,
+		City:    result.Localidade,
+		State:   result.Uf,
+	}, nil
+}
+
+// OfflinePostal finds the state, and the city for the largest ones, from the
+// embedded CEP ranges.
+type OfflinePostal struct {
+	ranges []cepRange
+}
+
+type cepRange struct {
+	start string
+	end   string
+	state string
+	city  string
+}
+
+func NewOfflinePostal() *OfflinePostal {
+	o := &OfflinePostal{}
+
+	r := csv.NewReader(strings.NewReader(cepRanges))
+	_, _ = r.Read()
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		o.ranges = append(o.ranges, cepRange{
+			start: record[0],
+			end:   record[1],
+			state: record[2],
+			city:  record[3],
+		})
+	}
+	return o
+}
+
+func (o *OfflinePostal) Lookup(ctx context.Context, cep string) (*entity.PostalAddress, error) {
+	var found *cepRange
+	for i := range o.ranges {
+		cr := &o.ranges[i]
+		if cep < cr.start || cep > cr.end {
+			continue
+		}
+		// nested ranges are more specific
+		if found == nil || (cr.start >= found.start && cr.end <= found.end) {
+			found = cr
+		}
+	}
+	if found == nil {
+		return nil, entity.ErrCEPNotFound
+	}
+
+	return &entity.PostalAddress{
+		ZipCode: cep,
+		City:    found.city,
+		State:   found.state,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/restore/user/entity"
+	"testing"
+)
+
+func TestOfflinePostalLookup(t *testing.T) {
+	tests := []struct {
+		cep       string
+		wantState string
+		wantCity  string
+		wantErr   error
+	}{
+		{"01310100", "SP", "São Paulo", nil},
+		{"08499999", "SP", "São Paulo", nil},
+		{"08500000", "SP", "", nil},
+		{"13010000", "SP", "", nil},
+		{"20040002", "RJ", "Rio de Janeiro", nil},
+		{"23800000", "RJ", "", nil},
+		{"88015000", "SC", "Florianópolis", nil},
+		{"99999999", "RS", "", nil},
+		{"00999999", "", "", entity.ErrCEPNotFound},
+	}
+	o := NewOfflinePostal()
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			got, err := o.Lookup(context.Background(), tt.cep)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup(%q) error = %v, want %v", tt.cep, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.ZipCode != tt.cep || got.State != tt.wantState || got.City != tt.wantCity {
+				t.Errorf("Lookup(%q) = %q, %q, %q, want %q, %q", tt.cep, got.ZipCode, got.City, got.State, tt.wantCity, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestOfflinePostalPrefersNestedRanges(t *testing.T) {
+	state := cepRange{start: "10000000", end: "19999999", state: "XX"}
+	city := cepRange{start: "11000000", end: "11999999", state: "XX", city: "Capital"}
+	district := cepRange{start: "11500000", end: "11599999", state: "XX", city: "Distrito"}
+	tests := []struct {
+		name     string
+		ranges   []cepRange
+		cep      string
+		wantCity string
+	}{
+		{"outer range first", []cepRange{state, city}, "11000001", "Capital"},
+		{"inner range first", []cepRange{city, state}, "11000001", "Capital"},
+		{"three levels", []cepRange{state, district, city}, "11550000", "Distrito"},
+		{"outside the inner range", []cepRange{city, state}, "12000000", ""},
+		{"range bounds", []cepRange{state, city}, "11999999", "Capital"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &OfflinePostal{ranges: tt.ranges}
+			got, err := o.Lookup(context.Background(), tt.cep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.City != tt.wantCity {
+				t.Errorf("Lookup(%q) city = %q, want %q", tt.cep, got.City, tt.wantCity)
+			}
+		})
+	}
+}
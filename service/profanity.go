@@ -0,0 +1,43 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/restore/user/normalize"
+)
+
+type ProfanityConfig struct {
+	Words []string `yaml:"words"`
+}
+
+// ProfanityFilter masks the offensive words of a text.
+type ProfanityFilter interface {
+	Clean(text string) string
+}
+
+// WordList masks the configured words, matched regardless of case and accents.
+type WordList struct {
+	words map[string]bool
+}
+
+var wordPattern = regexp.MustCompile(`[\pL\pN]+`)
+
+func NewWordList(cfg *ProfanityConfig) *WordList {
+	w := &WordList{
+		words: map[string]bool{},
+	}
+	for _, word := range cfg.Words {
+		w.words[normalize.Fold(word)] = true
+	}
+	return w
+}
+
+func (w *WordList) Clean(text string) string {
+	return wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+		if !w.words[normalize.Fold(word)] {
+			return word
+		}
+		return strings.Repeat("*", len([]rune(word)))
+	})
+}
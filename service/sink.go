@@ -0,0 +1,30 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileSink appends tab separated records to a file, used by the senders that
+// write their messages instead of delivering them.
+type fileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (f *fileSink) write(fields ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	record := append([]string{time.Now().Format(time.RFC3339)}, fields...)
+	_, err = file.WriteString(strings.Join(record, "\t") + "\n")
+	return err
+}
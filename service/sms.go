@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/restore/user/entity"
+	"go.uber.org/zap"
+)
+
+type SMSConfig struct {
+	Gateway        string        `yaml:"gateway"`
+	Token          string        `yaml:"token"`
+	Timeout        time.Duration `yaml:"timeout"`
+	Path           string        `yaml:"path"`
+	CodeTTL        time.Duration `yaml:"code_ttl"`
+	ResendInterval time.Duration `yaml:"resend_interval"`
+	MaxSends       int           `yaml:"max_sends"`
+}
+
+// SMSSender sends a text message to an E.164 phone number.
+type SMSSender interface {
+	Send(phone string, message string) error
+}
+
+// NewSMS creates the gateway sender, or the file sender for tests when only a
+// path is set. Without either, sending fails instead of dropping the message.
+func NewSMS(cfg *SMSConfig) SMSSender {
+	switch {
+	case cfg.Gateway != "":
+		return NewGatewaySMS(cfg)
+	case cfg.Path != "":
+		return NewFileSMS(cfg)
+	default:
+		return DisabledSMS{}
+	}
+}
+
+// GatewaySMS sends the messages through an HTTP SMS gateway, posting the
+// phone and message as JSON with the token as bearer.
+type GatewaySMS struct {
+	cfg    *SMSConfig
+	client *http.Client
+}
+
+type gatewayRequest struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+func NewGatewaySMS(cfg *SMSConfig) *GatewaySMS {
+	return &GatewaySMS{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+func (g *GatewaySMS) Send(phone string, message string) error {
+	log := zap.NewNop()
+
+	body, err := json.Marshal(gatewayRequest{To: phone, Message: message})
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequest(http.MethodPost, g.cfg.Gateway, bytes.NewReader(body))
+	if err != nil {
+		log.Error(
+			"error creating sms request",
+			zap.Error(err),
+		)
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if g.cfg.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+g.cfg.Token)
+	}
+
+	resp, err := g.client.Do(r)
+	if err != nil {
+		log.Error(
+			"error making sms request",
+			zap.Error(err),
+		)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Error(
+			"error sending sms",
+			zap.Any("status_code", resp.StatusCode),
+		)
+		return errors.New("error sending sms")
+	}
+	return nil
+}
+
+// FileSMS writes the messages to a file instead of sending them, for tests.
+type FileSMS struct {
+	sink *fileSink
+}
+
+func NewFileSMS(cfg *SMSConfig) *FileSMS {
+	return &FileSMS{
+		sink: &fileSink{path: cfg.Path},
+	}
+}
+
+func (f *FileSMS) Send(phone string, message string) error {
+	return f.sink.write(phone, message)
+}
+
+// DisabledSMS refuses every message, used when no sender is configured.
+type DisabledSMS struct{}
+
+func (DisabledSMS) Send(phone string, message string) error {
+	return entity.ErrSMSUnavailable
+}
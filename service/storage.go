@@ -0,0 +1,29 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const uploadDir = "uploads"
+
+type Storage struct{}
+
+func NewStorage() *Storage {
+	return &Storage{}
+}
+
+// Delete removes an uploaded file, files that are already gone are ignored.
+func (s *Storage) Delete(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	fileName := strings.TrimSuffix(filepath.Base(name), ".png")
+	err := os.Remove(filepath.Join(uploadDir, fileName+".png"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/restore/user/entity"
+	"github.com/restore/user/normalize"
+)
+
+// maxSuggestScan bounds how many index entries a suggestion looks at, so short
+// prefixes stay fast however many stores share them.
+const maxSuggestScan = 1000
+
+// Suggester finds stores by the prefix of their names.
+type Suggester interface {
+	Load(stores []entity.StoreSuggestion)
+	Put(store entity.StoreSuggestion)
+	Remove(id int)
+	Suggest(prefix string, limit int) []entity.StoreSuggestion
+}
+
+type suggestEntry struct {
+	key  string
+	id   int
+	full bool
+}
+
+// PrefixIndex keeps the store names in memory, sorted by their folded text
+// from each word on, so both "sao paulo" and "paulo" find "São Paulo Brechó".
+type PrefixIndex struct {
+	mu      sync.RWMutex
+	entries []suggestEntry
+	stores  map[int]entity.StoreSuggestion
+}
+
+func NewPrefixIndex() *PrefixIndex {
+	return &PrefixIndex{
+		stores: map[int]entity.StoreSuggestion{},
+	}
+}
+
+// Load replaces the whole index.
+func (p *PrefixIndex) Load(stores []entity.StoreSuggestion) {
+	entries := make([]suggestEntry, 0, len(stores))
+	byID := make(map[int]entity.StoreSuggestion, len(stores))
+	for _, store := range stores {
+		byID[store.ID] = store
+		entries = append(entries, suggestKeys(store)...)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entryLess(entries[i], entries[j])
+	})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = entries
+	p.stores = byID
+}
+
+// Put adds a store to the index, or replaces it when it's already there.
+func (p *PrefixIndex) Put(store entity.StoreSuggestion) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.remove(store.ID)
+	p.stores[store.ID] = store
+	for _, entry := range suggestKeys(store) {
+		i := p.search(entry)
+		p.entries = append(p.entries, suggestEntry{})
+		copy(p.entries[i+1:], p.entries[i:])
+		p.entries[i] = entry
+	}
+}
+
+// Remove drops a store from the index.
+func (p *PrefixIndex) Remove(id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.remove(id)
+}
+
+// Suggest returns up to limit stores whose name, or a word of it onwards,
+// starts with the prefix. Names starting with the prefix come first.
+func (p *PrefixIndex) Suggest(prefix string, limit int) []entity.StoreSuggestion {
+	key := suggestKey(normalize.Fold(prefix))
+	result := []entity.StoreSuggestion{}
+	if key == "" || limit <= 0 {
+		return result
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var found []suggestEntry
+	start := p.search(suggestEntry{key: key})
+	for i := start; i < len(p.entries) && i-start < maxSuggestScan; i++ {
+		if !strings.HasPrefix(p.entries[i].key, key) {
+			break
+		}
+		found = append(found, p.entries[i])
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].full && !found[j].full
+	})
+
+	seen := map[int]bool{}
+	for _, entry := range found {
+		if seen[entry.id] {
+			continue
+		}
+		seen[entry.id] = true
+		result = append(result, p.stores[entry.id])
+		if len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+func (p *PrefixIndex) remove(id int) {
+	store, ok := p.stores[id]
+	if !ok {
+		return
+	}
+	delete(p.stores, id)
+
+	for _, entry := range suggestKeys(store) {
+		i := p.search(entry)
+		if i < len(p.entries) && p.entries[i] == entry {
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+		}
+	}
+}
+
+// search finds where the entry is, or would be, in the sorted entries.
+func (p *PrefixIndex) search(entry suggestEntry) int {
+	return sort.Search(len(p.entries), func(i int) bool {
+		return !entryLess(p.entries[i], entry)
+	})
+}
+
+func entryLess(a, b suggestEntry) bool {
+	if a.key != b.key {
+		return a.key < b.key
+	}
+	return a.id < b.id
+}
+
+// suggestKeys lists the folded name from each of its words on.
+func suggestKeys(store entity.StoreSuggestion) []suggestEntry {
+	words := suggestWords(normalize.Fold(store.Name))
+	entries := make([]suggestEntry, 0, len(words))
+	for i := range words {
+		entries = append(entries, suggestEntry{
+			key:  strings.Join(words[i:], " "),
+			id:   store.ID,
+			full: i == 0,
+		})
+	}
+	return entries
+}
+
+func suggestKey(text string) string {
+	return strings.Join(suggestWords(text), " ")
+}
+
+func suggestWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !isSuggestRune(r)
+	})
+}
+
+func isSuggestRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
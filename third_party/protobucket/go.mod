@@ -0,0 +1,8 @@
+module github.com/ReStorePUC/protobucket
+
+go 1.21
+
+require (
+	google.golang.org/grpc v1.58.2
+	google.golang.org/protobuf v1.31.0
+)